@@ -0,0 +1,54 @@
+// Copyright 2025 Stacklok, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package files
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"sort"
+)
+
+// textEdit replaces the bytes from start to end of a file with text.
+type textEdit struct {
+	start, end int
+	text       string
+}
+
+// applyEdits applies non-overlapping edits to a copy of src.
+func applyEdits(src []byte, edits []textEdit) []byte {
+	sorted := append([]textEdit{}, edits...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].start > sorted[j].start })
+
+	out := append([]byte{}, src...)
+	for _, e := range sorted {
+		out = append(out[:e.start], append([]byte(e.text), out[e.end:]...)...)
+	}
+	return out
+}
+
+// keepFormatted formats the edited Go source if the original was gofmt-formatted, since
+// an edit can change alignment or import order; other files are left as edited.
+func keepFormatted(filename string, original, edited []byte) ([]byte, error) {
+	formatted, err := format.Source(original)
+	if err != nil || !bytes.Equal(formatted, original) {
+		return edited, nil
+	}
+	out, err := format.Source(edited)
+	if err != nil {
+		return nil, fmt.Errorf("formatting %s: %w", filename, err)
+	}
+	return out, nil
+}
@@ -0,0 +1,107 @@
+// Copyright 2025 Stacklok, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package files
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestApplyEdits(t *testing.T) {
+	t.Parallel()
+
+	src := []byte("version: 1.2.3\ntag: v1.2.3\n")
+	tests := []struct {
+		name  string
+		edits []textEdit
+		want  string
+	}{
+		{name: "no edits", want: "version: 1.2.3\ntag: v1.2.3\n"},
+		{
+			name:  "edits in any order",
+			edits: []textEdit{{start: 21, end: 26, text: "1.10.0"}, {start: 9, end: 14, text: "1.10.0"}},
+			want:  "version: 1.10.0\ntag: v1.10.0\n",
+		},
+		{
+			name:  "insertion and deletion",
+			edits: []textEdit{{start: 0, end: 0, text: "# app\n"}, {start: 15, end: 27}},
+			want:  "# app\nversion: 1.2.3\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := string(applyEdits(src, tt.edits)); got != tt.want {
+				t.Errorf("applyEdits() = %q, want %q", got, tt.want)
+			}
+			if string(src) != "version: 1.2.3\ntag: v1.2.3\n" {
+				t.Errorf("applyEdits() modified its input: %q", src)
+			}
+		})
+	}
+}
+
+func TestKeepFormatted(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		original string
+		edited   string
+		want     string
+		wantErr  string
+	}{
+		{
+			name:     "formatted source is reformatted",
+			original: "package v\n\nconst (\n\tA  = \"1\"\n\tBB = \"2\"\n)\n",
+			edited:   "package v\n\nconst (\n\tA  = \"1\"\n\tBBB = \"2\"\n)\n",
+			want:     "package v\n\nconst (\n\tA   = \"1\"\n\tBBB = \"2\"\n)\n",
+		},
+		{
+			name:     "unformatted source is left as edited",
+			original: "package v\nconst A  =  \"1\"\n",
+			edited:   "package v\nconst A  =  \"2\"\n",
+			want:     "package v\nconst A  =  \"2\"\n",
+		},
+		{
+			name:     "edit breaking formatted source",
+			original: "package v\n\nconst A = \"1\"\n",
+			edited:   "package v\n\nconst A = \"1\n",
+			wantErr:  "formatting version.go",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := keepFormatted("version.go", []byte(tt.original), []byte(tt.edited))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("keepFormatted() error = %v, want to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("keepFormatted() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("keepFormatted() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+// Copyright 2025 Stacklok, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package files
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// UpdateGoConstant updates the string literal assigned to the package-level constant or
+// variable named cfg.Path in the Go source file cfg.File. The literal is located with
+// go/parser, so build constraints and grouped declarations need no special handling. The
+// literal must hold exactly the current version as cfg.Prefix or cfg.Template writes it,
+// so that a placeholder such as "dev" is not overwritten, and with cfg.Bump it must hold
+// a version; see VersionFileConfig. Only the literal is rewritten; a gofmt-formatted file
+// stays so.
+func UpdateGoConstant(cfg VersionFileConfig, currentVersion, newVersion string) error {
+	data, err := os.ReadFile(cfg.File)
+	if err != nil {
+		return fmt.Errorf("reading file %s: %w", cfg.File, err)
+	}
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, cfg.File, data, parser.ParseComments)
+	if err != nil {
+		return fmt.Errorf("parsing %s: %w", cfg.File, err)
+	}
+	lit, err := packageLiteral(file, cfg.Path)
+	if err != nil {
+		return fmt.Errorf("%s: %w", cfg.File, err)
+	}
+	value, err := strconv.Unquote(lit.Value)
+	if err != nil {
+		return fmt.Errorf("%s: decoding %s: %w", cfg.File, cfg.Path, err)
+	}
+
+	var newValue string
+	if cfg.Bump != "" {
+		newValue, err = bumpOwnVersion(cfg, value)
+	} else {
+		newValue, err = goLiteralValue(cfg, value, currentVersion, newVersion)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Keep a raw string raw, unless the new value cannot be written as one
+	newLit := strconv.Quote(newValue)
+	if strings.HasPrefix(lit.Value, "`") && strconv.CanBackquote(newValue) {
+		newLit = "`" + newValue + "`"
+	}
+	start := fset.Position(lit.Pos()).Offset
+	edited := applyEdits(data, []textEdit{{start: start, end: start + len(lit.Value), text: newLit}})
+	if data, err = keepFormatted(cfg.File, data, edited); err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(cfg.File, data, 0644); err != nil {
+		return fmt.Errorf("writing file %s: %w", cfg.File, err)
+	}
+	return nil
+}
+
+// goLiteralValue returns the value replacing value, which must be the current version
+// formatted for cfg.
+func goLiteralValue(cfg VersionFileConfig, value, currentVersion, newVersion string) (string, error) {
+	oldVersionStr, err := cfg.formatVersion(currentVersion)
+	if err != nil {
+		return "", err
+	}
+	if value != oldVersionStr {
+		return "", fmt.Errorf("version mismatch in %s at %s: expected %q but found %q. "+
+			"Please manually update the version in this file to %q before running releaseo",
+			cfg.File, cfg.Path, oldVersionStr, value, oldVersionStr)
+	}
+	return cfg.formatVersion(newVersion)
+}
+
+// packageLiteral returns the string literal assigned to the package-level constant or
+// variable name.
+func packageLiteral(file *ast.File, name string) (*ast.BasicLit, error) {
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || (gen.Tok != token.CONST && gen.Tok != token.VAR) {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			valueSpec := spec.(*ast.ValueSpec)
+			for i, ident := range valueSpec.Names {
+				if ident.Name != name {
+					continue
+				}
+				if i < len(valueSpec.Values) {
+					if lit, ok := valueSpec.Values[i].(*ast.BasicLit); ok && lit.Kind == token.STRING {
+						return lit, nil
+					}
+				}
+				return nil, fmt.Errorf("%s is not assigned a string literal", name)
+			}
+		}
+	}
+	return nil, fmt.Errorf("no package-level constant or variable %s", name)
+}
@@ -0,0 +1,158 @@
+// Copyright 2025 Stacklok, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package files
+
+import (
+	"strings"
+	"testing"
+)
+
+const goVersionFile = `//go:build !dev
+
+// Package buildinfo describes the build.
+package buildinfo
+
+// Build information.
+const (
+	Name    = "app"   // binary name
+	Version = "1.2.3" // set by releaseo
+	Commit  = ` + "`unknown`" + `
+)
+
+var Channel, Tag = "stable", "v1.2.3"
+
+const Major = 1
+`
+
+func TestUpdateGoConstant(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		input      string
+		cfg        VersionFileConfig
+		newVersion string
+		want       string
+		wantErr    string
+	}{
+		{
+			name:       "const in group, realigning comments",
+			input:      goVersionFile,
+			cfg:        VersionFileConfig{Path: "Version"},
+			newVersion: "1.2.10",
+			want: strings.NewReplacer(
+				`Name    = "app"   // binary name`, `Name    = "app"    // binary name`,
+				`Version = "1.2.3" // set by releaseo`, `Version = "1.2.10" // set by releaseo`,
+			).Replace(goVersionFile),
+		},
+		{
+			name:       "var with several names and prefix",
+			input:      goVersionFile,
+			cfg:        VersionFileConfig{Path: "Tag", Prefix: "v"},
+			newVersion: "1.3.0",
+			want:       strings.Replace(goVersionFile, `"v1.2.3"`, `"v1.3.0"`, 1),
+		},
+		{
+			name:       "raw string",
+			input:      "package version\n\nconst Version = `1.2.3`\n",
+			cfg:        VersionFileConfig{Path: "Version"},
+			newVersion: "1.3.0",
+			want:       "package version\n\nconst Version = `1.3.0`\n",
+		},
+		{
+			name:       "unformatted file is not reformatted",
+			input:      "package version\nconst   Version=\"1.2.3\"\n",
+			cfg:        VersionFileConfig{Path: "Version"},
+			newVersion: "1.3.0",
+			want:       "package version\nconst   Version=\"1.3.0\"\n",
+		},
+		{
+			name:       "own version stream",
+			input:      "package version\n\nconst API = \"0.4.1\"\n",
+			cfg:        VersionFileConfig{Path: "API", Bump: "minor"},
+			newVersion: "1.3.0",
+			want:       "package version\n\nconst API = \"0.5.0\"\n",
+		},
+		{
+			name:       "version mismatch",
+			input:      "package version\n\nconst Version = \"1.2.2\"\n",
+			cfg:        VersionFileConfig{Path: "Version"},
+			newVersion: "1.3.0",
+			wantErr:    "version mismatch",
+		},
+		{
+			name:       "placeholder is not overwritten",
+			input:      "package version\n\nvar Version = \"dev\"\n",
+			cfg:        VersionFileConfig{Path: "Version"},
+			newVersion: "1.3.0",
+			wantErr:    `expected "1.2.3" but found "dev"`,
+		},
+		{
+			name:       "embedded version",
+			input:      "package version\n\nconst UserAgent = \"app/1.2.3\"\n",
+			cfg:        VersionFileConfig{Path: "UserAgent"},
+			newVersion: "1.3.0",
+			wantErr:    "version mismatch",
+		},
+		{
+			name:       "not a string literal",
+			input:      goVersionFile,
+			cfg:        VersionFileConfig{Path: "Major"},
+			newVersion: "1.3.0",
+			wantErr:    "not assigned a string literal",
+		},
+		{
+			name:       "not found",
+			input:      "package version\n\nfunc f() { const Version = \"1.2.3\" }\n",
+			cfg:        VersionFileConfig{Path: "Version"},
+			newVersion: "1.3.0",
+			wantErr:    "no package-level constant or variable Version",
+		},
+		{
+			name:       "invalid Go",
+			input:      "package version\n\nconst Version = \n",
+			cfg:        VersionFileConfig{Path: "Version"},
+			newVersion: "1.3.0",
+			wantErr:    "parsing",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := tt.cfg
+			cfg.Type = TypeGo
+			cfg.File = createTempFile(t, tt.input, "version-*.go")
+
+			err := (&DefaultYAMLUpdater{}).UpdateYAMLFile(cfg, "1.2.3", tt.newVersion)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("UpdateGoConstant() error = %v, want to contain %q", err, tt.wantErr)
+				}
+				if got := readTempFile(t, cfg.File); got != tt.input {
+					t.Errorf("file modified on error:\n%s", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("UpdateGoConstant() error = %v", err)
+			}
+			if got := readTempFile(t, cfg.File); got != tt.want {
+				t.Errorf("UpdateGoConstant() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
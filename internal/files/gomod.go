@@ -0,0 +1,256 @@
+// Copyright 2025 Stacklok, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package files
+
+import (
+	"errors"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/stacklok/releaseo/internal/version"
+)
+
+var (
+	// moduleDirective matches the module directive of a go.mod file, with an optionally quoted path.
+	moduleDirective = regexp.MustCompile(`(?m)^(\s*module\s+)("?)([^\s"]+)"?`)
+	// majorSuffix matches the major version suffix of a module path, such as /v2.
+	majorSuffix = regexp.MustCompile(`^(.+)/v([0-9]+)$`)
+)
+
+// UpdateGoModule moves the Go module whose go.mod is cfg.File to the major version of
+// newVersion, as Go's semantic import versioning requires from v2 on: the /vN suffix of
+// the module path is set in go.mod and every import of the module in its .go files is
+// rewritten. Nested modules, vendor and testdata directories are left alone. Bumps that
+// keep the major version, or stay below v2, change nothing.
+func UpdateGoModule(cfg VersionFileConfig, currentVersion, newVersion string) error {
+	current, err := version.Parse(currentVersion)
+	if err != nil {
+		return fmt.Errorf("parsing current version: %w", err)
+	}
+	next, err := version.Parse(newVersion)
+	if err != nil {
+		return fmt.Errorf("parsing new version: %w", err)
+	}
+	if next.Major == current.Major || next.Major < 2 {
+		return nil
+	}
+
+	data, err := os.ReadFile(cfg.File)
+	if err != nil {
+		return fmt.Errorf("reading file %s: %w", cfg.File, err)
+	}
+	oldPath, err := modulePath(data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", cfg.File, err)
+	}
+
+	base, major := splitMajorSuffix(oldPath)
+	if major != max(current.Major, 1) {
+		return fmt.Errorf("version mismatch in %s: module path %s does not match current version %s. "+
+			"Please fix the module path before running releaseo", cfg.File, oldPath, currentVersion)
+	}
+	newPath := fmt.Sprintf("%s/v%d", base, next.Major)
+
+	// Compute every change before writing, so that a file that fails to parse leaves the module untouched
+	dir := filepath.Dir(cfg.File)
+	goFiles, nested, err := moduleGoFiles(dir)
+	if err != nil {
+		return err
+	}
+	updated := map[string][]byte{}
+	for _, path := range goFiles {
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("reading file %s: %w", path, err)
+		}
+		out, changed, err := rewriteImports(path, src, oldPath, newPath, nested)
+		if err != nil {
+			return err
+		}
+		if changed {
+			updated[path] = out
+		}
+	}
+	updated[cfg.File] = moduleDirective.ReplaceAll(data, []byte("${1}${2}"+newPath+"${2}"))
+
+	paths := make([]string, 0, len(updated))
+	for path := range updated {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		if err := os.WriteFile(path, updated[path], 0644); err != nil {
+			return fmt.Errorf("writing file %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// goModuleFiles returns the files UpdateGoModule changes when moving from currentVersion
+// to newVersion: go.mod alone, plus the .go files of its module that import the module
+// when the bump moves to a new major version from v2 on.
+func goModuleFiles(goMod, currentVersion, newVersion string) []string {
+	files := []string{goMod}
+
+	current, err := version.Parse(currentVersion)
+	if err != nil {
+		return files
+	}
+	next, err := version.Parse(newVersion)
+	if err != nil || next.Major == current.Major || next.Major < 2 {
+		return files
+	}
+	data, err := os.ReadFile(goMod)
+	if err != nil {
+		return files
+	}
+	modPath, err := modulePath(data)
+	if err != nil {
+		return files
+	}
+	goFiles, nested, err := moduleGoFiles(filepath.Dir(goMod))
+	if err != nil {
+		return files
+	}
+	for _, path := range goFiles {
+		src, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		// Rewriting to the same path reports whether the file imports the module
+		if _, imports, err := rewriteImports(path, src, modPath, modPath, nested); err == nil && imports {
+			files = append(files, path)
+		}
+	}
+	return files
+}
+
+// modulePath returns the module path declared in a go.mod file.
+func modulePath(data []byte) (string, error) {
+	m := moduleDirective.FindSubmatch(data)
+	if m == nil {
+		return "", fmt.Errorf("no module directive")
+	}
+	return string(m[3]), nil
+}
+
+// splitMajorSuffix splits a module path into its base and major version; paths without
+// a /vN suffix are major version 1.
+func splitMajorSuffix(path string) (string, int) {
+	if m := majorSuffix.FindStringSubmatch(path); m != nil {
+		if major, err := strconv.Atoi(m[2]); err == nil && major >= 2 {
+			return m[1], major
+		}
+	}
+	return path, 1
+}
+
+// moduleGoFiles returns the .go files of the module rooted at dir, and the module paths
+// of nested modules, whose directories are skipped.
+func moduleGoFiles(dir string) (goFiles, nested []string, err error) {
+	err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			if strings.HasSuffix(path, ".go") {
+				goFiles = append(goFiles, path)
+			}
+			return nil
+		}
+		if path == dir {
+			return nil
+		}
+
+		name := d.Name()
+		if name == "vendor" || name == "testdata" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") {
+			return filepath.SkipDir
+		}
+		data, err := os.ReadFile(filepath.Join(path, "go.mod"))
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if modPath, err := modulePath(data); err == nil {
+			nested = append(nested, modPath)
+		}
+		return filepath.SkipDir
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("listing Go files in %s: %w", dir, err)
+	}
+	return goFiles, nested, nil
+}
+
+// rewriteImports replaces oldPath with newPath in the import paths of a Go source file
+// that import the module, leaving imports of nested modules alone. Only the import path
+// literals are edited; a file that was gofmt-formatted is formatted again, since the new
+// paths may change the sort order of its imports. It reports whether any import matched.
+func rewriteImports(filename string, src []byte, oldPath, newPath string, nested []string) ([]byte, bool, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, src, parser.ImportsOnly|parser.ParseComments)
+	if err != nil {
+		return nil, false, fmt.Errorf("parsing %s: %w", filename, err)
+	}
+
+	var edits []textEdit
+	for _, imp := range file.Imports {
+		path, err := strconv.Unquote(imp.Path.Value)
+		if err != nil || !withinModule(path, oldPath) || withinAny(path, nested) {
+			continue
+		}
+		quote := imp.Path.Value[:1]
+		start := fset.Position(imp.Path.Pos()).Offset
+		edits = append(edits, textEdit{
+			start: start,
+			end:   start + len(imp.Path.Value),
+			text:  quote + newPath + strings.TrimPrefix(path, oldPath) + quote,
+		})
+	}
+	if len(edits) == 0 {
+		return src, false, nil
+	}
+
+	out, err := keepFormatted(filename, src, applyEdits(src, edits))
+	if err != nil {
+		return nil, false, err
+	}
+	return out, true, nil
+}
+
+// withinModule reports whether the import path belongs to the module path.
+func withinModule(path, module string) bool {
+	return path == module || strings.HasPrefix(path, module+"/")
+}
+
+// withinAny reports whether the import path belongs to any of the modules.
+func withinAny(path string, modules []string) bool {
+	for _, module := range modules {
+		if withinModule(path, module) {
+			return true
+		}
+	}
+	return false
+}
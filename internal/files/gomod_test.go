@@ -0,0 +1,251 @@
+// Copyright 2025 Stacklok, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package files
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const goModuleMain = `package main
+
+import (
+	"fmt"
+
+	"example.com/app/internal/util"
+	"example.com/app/tools/gen"
+	"example.com/appendix"
+)
+
+func main() { fmt.Println(util.Name, gen.Name, appendix.Name) }
+`
+
+const goModuleUtil = `package util
+
+import "example.com/app" // the root package
+
+const Name = app.Name
+`
+
+// An unformatted file is only edited, not reformatted.
+const goModuleUnformatted = `package app
+import   (  "example.com/app/internal/util" )
+var _ =   util.Name
+`
+
+func TestUpdateGoModule(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		module         string
+		currentVersion string
+		newVersion     string
+		wantModule     string
+		wantImport     string
+	}{
+		{
+			name:           "v1 to v2",
+			module:         "example.com/app",
+			currentVersion: "1.4.0",
+			newVersion:     "2.0.0",
+			wantModule:     "example.com/app/v2",
+			wantImport:     "example.com/app/v2",
+		},
+		{
+			name:           "v2 to v3",
+			module:         "example.com/app/v2",
+			currentVersion: "2.1.0",
+			newVersion:     "3.0.0",
+			wantModule:     "example.com/app/v3",
+			wantImport:     "example.com/app/v3",
+		},
+		{
+			name:           "v0 to v1 keeps the path",
+			module:         "example.com/app",
+			currentVersion: "0.9.0",
+			newVersion:     "1.0.0",
+			wantModule:     "example.com/app",
+			wantImport:     "example.com/app",
+		},
+		{
+			name:           "minor bump keeps the path",
+			module:         "example.com/app/v2",
+			currentVersion: "2.1.0",
+			newVersion:     "2.2.0",
+			wantModule:     "example.com/app/v2",
+			wantImport:     "example.com/app/v2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			imports := strings.NewReplacer(`"example.com/app/internal`, `"`+tt.module+`/internal`, `"example.com/app"`, `"`+tt.module+`"`)
+			dir := writeChartFiles(t, map[string]string{
+				"go.mod":                  "module " + tt.module + "\n\ngo 1.22\n",
+				"app.go":                  "package app\n\nconst Name = \"app\"\n",
+				"cmd/app/main.go":         imports.Replace(goModuleMain),
+				"internal/util/util.go":   imports.Replace(goModuleUtil),
+				"unformatted.go":          imports.Replace(goModuleUnformatted),
+				"tools/go.mod":            "module example.com/app/tools\n",
+				"tools/gen/gen.go":        "package gen\n\nconst Name = \"gen\"\n",
+				"testdata/fixture/x.go":   "package x\n\nimport _ \"example.com/app/internal/util\"\n",
+				"vendor/example.com/x.go": "package x\n\nimport _ \"example.com/app\"\n",
+			})
+
+			cfg := VersionFileConfig{File: filepath.Join(dir, "go.mod"), Type: TypeGoMod}
+			if err := UpdateGoModule(cfg, tt.currentVersion, tt.newVersion); err != nil {
+				t.Fatalf("UpdateGoModule() error = %v", err)
+			}
+
+			want := map[string]string{
+				"go.mod":                "module " + tt.wantModule + "\n\ngo 1.22\n",
+				"internal/util/util.go": strings.Replace(goModuleUtil, `"example.com/app"`, `"`+tt.wantImport+`"`, 1),
+				"unformatted.go":        strings.Replace(goModuleUnformatted, "example.com/app/", tt.wantImport+"/", 1),
+				"tools/gen/gen.go":      "package gen\n\nconst Name = \"gen\"\n",
+				"testdata/fixture/x.go": "package x\n\nimport _ \"example.com/app/internal/util\"\n",
+			}
+			for name, content := range want {
+				if got := readTempFile(t, filepath.Join(dir, name)); got != content {
+					t.Errorf("%s =\n%s\nwant\n%s", name, got, content)
+				}
+			}
+			if main := readTempFile(t, filepath.Join(dir, "cmd/app/main.go")); !strings.Contains(main, `"`+tt.wantImport+`/internal/util"`) ||
+				!strings.Contains(main, `"example.com/app/tools/gen"`) || !strings.Contains(main, `"example.com/appendix"`) {
+				t.Errorf("cmd/app/main.go imports not rewritten as expected:\n%s", main)
+			}
+		})
+	}
+}
+
+func TestUpdateGoModule_SortsImports(t *testing.T) {
+	t.Parallel()
+
+	// After the bump, the module's import sorts after the nested module's
+	dir := writeChartFiles(t, map[string]string{
+		"go.mod":       "module example.com/app\n",
+		"tools/go.mod": "module example.com/app/tools\n",
+		"main.go":      "package main\n\nimport (\n\t\"example.com/app/internal/util\"\n\t\"example.com/app/tools/gen\"\n)\n",
+	})
+
+	cfg := VersionFileConfig{File: filepath.Join(dir, "go.mod"), Type: TypeGoMod}
+	if err := UpdateGoModule(cfg, "1.0.0", "2.0.0"); err != nil {
+		t.Fatalf("UpdateGoModule() error = %v", err)
+	}
+
+	want := "package main\n\nimport (\n\t\"example.com/app/tools/gen\"\n\t\"example.com/app/v2/internal/util\"\n)\n"
+	if got := readTempFile(t, filepath.Join(dir, "main.go")); got != want {
+		t.Errorf("main.go =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestUpdateGoModule_Errors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		files   map[string]string
+		wantErr string
+	}{
+		{
+			name:    "module path ahead of the version",
+			files:   map[string]string{"go.mod": "module example.com/app/v2\n"},
+			wantErr: "version mismatch",
+		},
+		{
+			name:    "no module directive",
+			files:   map[string]string{"go.mod": "go 1.22\n"},
+			wantErr: "no module directive",
+		},
+		{
+			name: "unparsable Go file",
+			files: map[string]string{
+				"go.mod":  "module example.com/app\n",
+				"main.go": "package main\n\nimport \"example.com/app/internal/util\"\n",
+				"bad.go":  "package main\n\nimport (\n",
+			},
+			wantErr: "parsing",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			dir := writeChartFiles(t, tt.files)
+			cfg := VersionFileConfig{File: filepath.Join(dir, "go.mod"), Type: TypeGoMod}
+			err := UpdateGoModule(cfg, "1.2.0", "2.0.0")
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("UpdateGoModule() error = %v, want to contain %q", err, tt.wantErr)
+			}
+			for name, content := range tt.files {
+				if got := readTempFile(t, filepath.Join(dir, name)); got != content {
+					t.Errorf("%s modified on error:\n%s", name, got)
+				}
+			}
+		})
+	}
+}
+
+func TestVersionFileConfig_ModifiedFiles_GoMod(t *testing.T) {
+	t.Parallel()
+
+	dir := writeChartFiles(t, map[string]string{
+		"go.mod":          "module example.com/app/v2\n",
+		"cmd/app/main.go": "package main\n\nimport _ \"example.com/app/v2\"\n",
+		"tools/go.mod":    "module example.com/app/v2/tools\n",
+		"tools/main.go":   "package main\n\nimport _ \"example.com/app/v2\"\n",
+	})
+	cfg := VersionFileConfig{File: filepath.Join(dir, "go.mod"), Type: TypeGoMod}
+	tests := []struct {
+		name           string
+		currentVersion string
+		newVersion     string
+		want           []string
+	}{
+		{
+			name:           "major bump",
+			currentVersion: "1.4.0",
+			newVersion:     "2.0.0",
+			want:           []string{filepath.Join(dir, "go.mod"), filepath.Join(dir, "cmd/app/main.go")},
+		},
+		{
+			name:           "minor bump",
+			currentVersion: "2.0.0",
+			newVersion:     "2.1.0",
+			want:           []string{filepath.Join(dir, "go.mod")},
+		},
+		{
+			name:           "major bump below v2",
+			currentVersion: "0.9.0",
+			newVersion:     "1.0.0",
+			want:           []string{filepath.Join(dir, "go.mod")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := cfg.ModifiedFiles(tt.currentVersion, tt.newVersion); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ModifiedFiles() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,313 @@
+// Copyright 2025 Stacklok, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package files
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+
+	"github.com/stacklok/releaseo/internal/version"
+)
+
+// Files of a Helm chart directory.
+const (
+	chartFileName = "Chart.yaml"
+	lockFileName  = "Chart.lock"
+)
+
+// localRepositoryPrefix marks a chart dependency stored in the local file system.
+const localRepositoryPrefix = "file://"
+
+// chartMetadata holds the fields of Chart.yaml that releaseo reads.
+type chartMetadata struct {
+	Version      string             `yaml:"version"`
+	AppVersion   string             `yaml:"appVersion"`
+	Dependencies []*chartDependency `yaml:"dependencies"`
+}
+
+// chartDependency mirrors Helm's chart.Dependency. The JSON field names and order must
+// match Helm's, since the Chart.lock digest is computed over its JSON encoding.
+type chartDependency struct {
+	Name         string   `yaml:"name" json:"name"`
+	Version      string   `yaml:"version" json:"version,omitempty"`
+	Repository   string   `yaml:"repository" json:"repository"`
+	Condition    string   `yaml:"condition" json:"condition,omitempty"`
+	Tags         []string `yaml:"tags" json:"tags,omitempty"`
+	Enabled      bool     `yaml:"enabled" json:"enabled,omitempty"`
+	ImportValues []any    `yaml:"import-values" json:"import-values,omitempty"`
+	Alias        string   `yaml:"alias" json:"alias,omitempty"`
+}
+
+// chartLock holds the fields of Chart.lock that releaseo reads.
+type chartLock struct {
+	Dependencies []*chartDependency `yaml:"dependencies"`
+	Digest       string             `yaml:"digest"`
+}
+
+// UpdateHelmChart bumps the chart in the directory cfg.File: its version is set to
+// newVersion (or bumped by cfg.Bump, if the chart has its own version stream), and its
+// appVersion (if present) to newVersion with cfg.Prefix or cfg.Template. The
+// versions of local (file://) dependencies are then synced with the charts they point
+// to, both in this chart and in the parent chart when it is nested in a charts/
+// directory, and the Chart.lock digests are regenerated the way helm does, so that
+// `helm dependency build` accepts them without the helm binary having to run here.
+// Warnings about an appVersion YAML would read as a number are only reported through
+// DefaultYAMLUpdater.
+func UpdateHelmChart(cfg VersionFileConfig, currentVersion, newVersion string) error {
+	return updateHelmChart(cfg, currentVersion, newVersion, nil)
+}
+
+// updateHelmChart implements UpdateHelmChart, reporting warnings to warn (optional).
+func updateHelmChart(cfg VersionFileConfig, currentVersion, newVersion string, warn func(string)) error {
+	chartPath := filepath.Join(cfg.File, chartFileName)
+	data, meta, err := readChart(cfg.File)
+	if err != nil {
+		return err
+	}
+
+	if meta.Version == "" {
+		return fmt.Errorf("chart %s has no version", chartPath)
+	}
+	// A chart version is always SemVer, so the prefix only applies to appVersion
+	versionCfg := VersionFileConfig{File: chartPath, Path: "version", Bump: cfg.Bump}
+	node, current, err := rawScalar(data, "$.version")
+	if err != nil {
+		return fmt.Errorf("reading version of %s: %w", chartPath, err)
+	}
+	var value string
+	if cfg.Bump != "" {
+		value, err = bumpOwnVersion(versionCfg, current)
+	} else {
+		value, err = replacementValue(versionCfg, current, currentVersion, newVersion)
+	}
+	if err != nil {
+		return err
+	}
+	edits := []scalarEdit{{path: "$.version", value: quoteRetyped(versionCfg, node, value, warn)}}
+
+	if meta.AppVersion != "" {
+		appCfg := VersionFileConfig{
+			File: chartPath, Path: "appVersion", Prefix: cfg.Prefix, Template: cfg.Template, Quote: cfg.Quote,
+		}
+		node, current, err := rawScalar(data, "$.appVersion")
+		if err != nil {
+			return fmt.Errorf("reading appVersion of %s: %w", chartPath, err)
+		}
+		value, err := replacementValue(appCfg, current, currentVersion, newVersion)
+		if err != nil {
+			return err
+		}
+		edits = append(edits, scalarEdit{path: "$.appVersion", value: quoteRetyped(appCfg, node, value, warn)})
+	}
+
+	if data, err = replaceScalars(data, edits); err != nil {
+		return fmt.Errorf("updating %s: %w", chartPath, err)
+	}
+	if err := os.WriteFile(chartPath, data, 0644); err != nil {
+		return fmt.Errorf("writing file %s: %w", chartPath, err)
+	}
+
+	if err := syncLocalDependencies(cfg.File); err != nil {
+		return err
+	}
+	if parent, ok := parentChart(cfg.File); ok {
+		return syncLocalDependencies(parent)
+	}
+	return nil
+}
+
+// helmChartFiles returns the Chart.yaml and Chart.lock files that updating the chart
+// in dir may change, including those of its parent chart.
+func helmChartFiles(dir string) []string {
+	dirs := []string{dir}
+	if parent, ok := parentChart(dir); ok {
+		dirs = append(dirs, parent)
+	}
+
+	var modified []string
+	for _, dir := range dirs {
+		modified = append(modified, filepath.Join(dir, chartFileName))
+		if _, err := os.Stat(filepath.Join(dir, lockFileName)); err == nil {
+			modified = append(modified, filepath.Join(dir, lockFileName))
+		}
+	}
+	return modified
+}
+
+// readChart reads and decodes the Chart.yaml of the chart in dir.
+func readChart(dir string) ([]byte, *chartMetadata, error) {
+	path := filepath.Join(dir, chartFileName)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("reading file %s: %w", path, err)
+	}
+	var meta chartMetadata
+	if err := yaml.Unmarshal(data, &meta); err != nil {
+		return nil, nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return data, &meta, nil
+}
+
+// parentChart returns the chart that dir is a subchart of, following helm's convention
+// of storing subcharts in the charts/ directory of their parent.
+func parentChart(dir string) (string, bool) {
+	dir = filepath.Clean(dir)
+	if filepath.Base(filepath.Dir(dir)) != "charts" {
+		return "", false
+	}
+	parent := filepath.Dir(filepath.Dir(dir))
+	if _, err := os.Stat(filepath.Join(parent, chartFileName)); err != nil {
+		return "", false
+	}
+	return parent, true
+}
+
+// resyncChartDependencies syncs the local dependencies of every helm entry that was
+// updated without error, recording failures in errs. Updating a chart only syncs the
+// chart itself and its parent, so a chart whose file:// dependency is a sibling chart
+// updated later in the list would otherwise keep the sibling's old version.
+func resyncChartDependencies(cfgs []VersionFileConfig, errs []error) {
+	for i, cfg := range cfgs {
+		if cfg.Type == TypeHelm && errs[i] == nil {
+			errs[i] = syncLocalDependencies(cfg.File)
+		}
+	}
+}
+
+// syncLocalDependencies sets the version of each file:// dependency of the chart in dir
+// to the version of the chart it points to, then regenerates Chart.lock. Dependencies
+// declared with a version range are left as they are, since helm resolves them itself.
+func syncLocalDependencies(dir string) error {
+	chartPath := filepath.Join(dir, chartFileName)
+	data, meta, err := readChart(dir)
+	if err != nil {
+		return err
+	}
+
+	local, err := localDependencyVersions(dir, meta.Dependencies)
+	if err != nil {
+		return err
+	}
+
+	var edits []scalarEdit
+	for i, dep := range meta.Dependencies {
+		resolved, ok := local[dep.Repository]
+		if !ok || dep.Version == resolved {
+			continue
+		}
+		if _, err := version.Parse(dep.Version); err != nil {
+			continue
+		}
+		edits = append(edits, scalarEdit{path: fmt.Sprintf("$.dependencies[%d].version", i), value: resolved})
+		dep.Version = resolved
+	}
+
+	if len(edits) > 0 {
+		if data, err = replaceScalars(data, edits); err != nil {
+			return fmt.Errorf("updating dependencies in %s: %w", chartPath, err)
+		}
+		if err := os.WriteFile(chartPath, data, 0644); err != nil {
+			return fmt.Errorf("writing file %s: %w", chartPath, err)
+		}
+	}
+
+	return updateChartLock(dir, meta.Dependencies, local)
+}
+
+// localDependencyVersions returns the current version of each file:// dependency's chart,
+// keyed by repository.
+func localDependencyVersions(dir string, deps []*chartDependency) (map[string]string, error) {
+	versions := map[string]string{}
+	for _, dep := range deps {
+		if !strings.HasPrefix(dep.Repository, localRepositoryPrefix) {
+			continue
+		}
+		subchart := strings.TrimPrefix(dep.Repository, localRepositoryPrefix)
+		if !filepath.IsAbs(subchart) {
+			subchart = filepath.Join(dir, subchart)
+		}
+		_, meta, err := readChart(subchart)
+		if err != nil {
+			return nil, fmt.Errorf("reading dependency %s of %s: %w", dep.Name, dir, err)
+		}
+		versions[dep.Repository] = meta.Version
+	}
+	return versions, nil
+}
+
+// updateChartLock updates the versions of local dependencies in the Chart.lock of the
+// chart in dir and regenerates its digest from the chart's dependencies. A chart
+// without a Chart.lock is left without one.
+func updateChartLock(dir string, deps []*chartDependency, local map[string]string) error {
+	lockPath := filepath.Join(dir, lockFileName)
+	data, err := os.ReadFile(lockPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("reading file %s: %w", lockPath, err)
+	}
+
+	var lock chartLock
+	if err := yaml.Unmarshal(data, &lock); err != nil {
+		return fmt.Errorf("parsing %s: %w", lockPath, err)
+	}
+
+	var edits []scalarEdit
+	for i, dep := range lock.Dependencies {
+		if resolved, ok := local[dep.Repository]; ok && dep.Version != resolved {
+			edits = append(edits, scalarEdit{path: fmt.Sprintf("$.dependencies[%d].version", i), value: resolved})
+			dep.Version = resolved
+		}
+	}
+
+	digest, err := lockDigest(deps, lock.Dependencies)
+	if err != nil {
+		return fmt.Errorf("computing digest of %s: %w", lockPath, err)
+	}
+	if digest != lock.Digest {
+		edits = append(edits, scalarEdit{path: "$.digest", value: digest})
+	}
+	if len(edits) == 0 {
+		return nil
+	}
+
+	if data, err = replaceScalars(data, edits); err != nil {
+		return fmt.Errorf("updating %s: %w", lockPath, err)
+	}
+	if err := os.WriteFile(lockPath, data, 0644); err != nil {
+		return fmt.Errorf("writing file %s: %w", lockPath, err)
+	}
+	return nil
+}
+
+// lockDigest computes the digest helm stores in Chart.lock: the SHA-256 of the JSON
+// encoding of the chart's dependencies and the locked dependencies (helm's resolver.HashReq).
+func lockDigest(deps, locked []*chartDependency) (string, error) {
+	data, err := json.Marshal([2][]*chartDependency{deps, locked})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,329 @@
+// Copyright 2025 Stacklok, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package files
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// writeChartFiles writes files relative to a temporary directory and returns it.
+func writeChartFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+const umbrellaChart = `apiVersion: v2
+name: platform
+version: 1.0.0 # bumped by releaseo
+appVersion: "v1.0.0"
+dependencies:
+  - name: api
+    version: 1.0.0
+    repository: file://charts/api
+  - name: common
+    version: "~1.0.0"
+    repository: file://../common
+  - name: redis
+    version: 1.0.0
+    repository: https://charts.example.com
+`
+
+const umbrellaLock = `dependencies:
+- name: api
+  repository: file://charts/api
+  version: 1.0.0
+- name: common
+  repository: file://../common
+  version: 1.0.3
+- name: redis
+  repository: https://charts.example.com
+  version: 1.0.0
+digest: sha256:0000000000000000000000000000000000000000000000000000000000000000
+generated: "2025-01-02T03:04:05.000000006Z"
+`
+
+func TestUpdateHelmChart(t *testing.T) {
+	t.Parallel()
+
+	dir := writeChartFiles(t, map[string]string{
+		"platform/Chart.yaml":            umbrellaChart,
+		"platform/Chart.lock":            umbrellaLock,
+		"platform/charts/api/Chart.yaml": "apiVersion: v2\nname: api\nversion: 1.0.0\nappVersion: 1.0.0\n",
+		"common/Chart.yaml":              "apiVersion: v2\nname: common\nversion: 1.0.3\n",
+	})
+
+	// Bumping the subchart also syncs its version in the parent chart
+	api := VersionFileConfig{File: filepath.Join(dir, "platform/charts/api"), Type: TypeHelm}
+	if err := UpdateHelmChart(api, "1.0.0", "1.1.0"); err != nil {
+		t.Fatalf("UpdateHelmChart(api) error = %v", err)
+	}
+	platform := VersionFileConfig{File: filepath.Join(dir, "platform"), Type: TypeHelm, Prefix: "v"}
+	if err := UpdateHelmChart(platform, "1.0.0", "1.1.0"); err != nil {
+		t.Fatalf("UpdateHelmChart(platform) error = %v", err)
+	}
+
+	wantAPI := "apiVersion: v2\nname: api\nversion: 1.1.0\nappVersion: 1.1.0\n"
+	if got := readTempFile(t, filepath.Join(dir, "platform/charts/api/Chart.yaml")); got != wantAPI {
+		t.Errorf("api Chart.yaml =\n%s\nwant\n%s", got, wantAPI)
+	}
+
+	// Only the parent's own version, appVersion and the local pinned dependency change;
+	// the range and the remote dependency with the same version are left alone
+	wantPlatform := strings.NewReplacer(
+		"version: 1.0.0 # bumped", "version: 1.1.0 # bumped",
+		`appVersion: "v1.0.0"`, `appVersion: "v1.1.0"`,
+		"version: 1.0.0\n    repository: file://charts/api", "version: 1.1.0\n    repository: file://charts/api",
+	).Replace(umbrellaChart)
+	if got := readTempFile(t, filepath.Join(dir, "platform/Chart.yaml")); got != wantPlatform {
+		t.Errorf("platform Chart.yaml =\n%s\nwant\n%s", got, wantPlatform)
+	}
+
+	lock := readTempFile(t, filepath.Join(dir, "platform/Chart.lock"))
+	if !strings.Contains(lock, "repository: file://charts/api\n  version: 1.1.0\n") {
+		t.Errorf("Chart.lock does not lock api at 1.1.0:\n%s", lock)
+	}
+	if !strings.Contains(lock, `generated: "2025-01-02T03:04:05.000000006Z"`) {
+		t.Errorf("Chart.lock generated timestamp changed:\n%s", lock)
+	}
+	_, meta, err := readChart(filepath.Join(dir, "platform"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantDigest, err := lockDigest(meta.Dependencies, []*chartDependency{
+		{Name: "api", Repository: "file://charts/api", Version: "1.1.0"},
+		{Name: "common", Repository: "file://../common", Version: "1.0.3"},
+		{Name: "redis", Repository: "https://charts.example.com", Version: "1.0.0"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(lock, "digest: "+wantDigest+"\n") {
+		t.Errorf("Chart.lock digest not regenerated, want %s:\n%s", wantDigest, lock)
+	}
+}
+
+func TestUpdateYAMLFiles_SiblingCharts(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		umbrellaFirst bool
+	}{
+		{name: "umbrella first", umbrellaFirst: true},
+		{name: "umbrella last"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			dir := writeChartFiles(t, map[string]string{
+				"charts/umbrella/Chart.yaml": "apiVersion: v2\nname: umbrella\nversion: 1.0.0\n" +
+					"dependencies:\n  - name: app\n    version: 1.0.0\n    repository: file://../app\n",
+				"charts/app/Chart.yaml": "apiVersion: v2\nname: app\nversion: 1.0.0\n",
+			})
+			umbrella := VersionFileConfig{File: filepath.Join(dir, "charts/umbrella"), Type: TypeHelm}
+			app := VersionFileConfig{File: filepath.Join(dir, "charts/app"), Type: TypeHelm}
+			cfgs := []VersionFileConfig{app, umbrella}
+			if tt.umbrellaFirst {
+				cfgs = []VersionFileConfig{umbrella, app}
+			}
+
+			for i, err := range (&DefaultYAMLUpdater{}).UpdateYAMLFiles(cfgs, "1.0.0", "1.1.0") {
+				if err != nil {
+					t.Fatalf("UpdateYAMLFiles() entry %d error = %v", i, err)
+				}
+			}
+
+			want := "apiVersion: v2\nname: umbrella\nversion: 1.1.0\n" +
+				"dependencies:\n  - name: app\n    version: 1.1.0\n    repository: file://../app\n"
+			if got := readTempFile(t, filepath.Join(umbrella.File, chartFileName)); got != want {
+				t.Errorf("umbrella Chart.yaml =\n%s\nwant\n%s", got, want)
+			}
+		})
+	}
+}
+
+func TestUpdateHelmChart_OwnVersionStream(t *testing.T) {
+	t.Parallel()
+
+	dir := writeChartFiles(t, map[string]string{
+		"Chart.yaml": "name: app\nversion: 0.7.3\nappVersion: v1.0.0\n",
+	})
+
+	cfg := VersionFileConfig{File: dir, Type: TypeHelm, Prefix: "v", Bump: BumpSame}.WithAppBump("patch")
+	if err := UpdateHelmChart(cfg, "1.0.0", "1.1.0"); err != nil {
+		t.Fatalf("UpdateHelmChart() error = %v", err)
+	}
+
+	// The chart version is bumped on its own; appVersion follows the app
+	want := "name: app\nversion: 0.7.4\nappVersion: v1.1.0\n"
+	if got := readTempFile(t, filepath.Join(dir, "Chart.yaml")); got != want {
+		t.Errorf("Chart.yaml =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestUpdateHelmChart_NumericAppVersion(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		appVersion  string
+		quote       bool
+		want        string
+		wantWarning string
+	}{
+		{name: "stays a number", appVersion: "1.2", want: "1.3"},
+		{name: "trailing zero", appVersion: "1.10", want: "1.11"},
+		{name: "losing its form", appVersion: "1.9", want: "1.10", wantWarning: "YAML reads 1.10 as a number"},
+		{name: "quoted", appVersion: "1.9", quote: true, want: `"1.10"`, wantWarning: "quoted 1.10"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			dir := writeChartFiles(t, map[string]string{
+				"Chart.yaml": "name: app\nversion: 0.1.0\nappVersion: " + tt.appVersion + "\n",
+			})
+			var warnings []string
+			updater := &DefaultYAMLUpdater{Warn: func(msg string) { warnings = append(warnings, msg) }}
+			cfg := VersionFileConfig{File: dir, Type: TypeHelm, Template: "{{ .Major }}.{{ .Minor }}", Quote: tt.quote, Bump: "patch"}
+
+			current := tt.appVersion + ".0"
+			next := strings.Trim(tt.want, `"`) + ".0"
+			if err := updater.UpdateYAMLFile(cfg, current, next); err != nil {
+				t.Fatalf("UpdateYAMLFile() error = %v", err)
+			}
+
+			want := "name: app\nversion: 0.1.1\nappVersion: " + tt.want + "\n"
+			if got := readTempFile(t, filepath.Join(dir, "Chart.yaml")); got != want {
+				t.Errorf("Chart.yaml =\n%s\nwant\n%s", got, want)
+			}
+			switch {
+			case tt.wantWarning == "" && len(warnings) > 0:
+				t.Errorf("UpdateYAMLFile() warnings = %q, want none", warnings)
+			case tt.wantWarning != "" && (len(warnings) != 1 || !strings.Contains(warnings[0], tt.wantWarning)):
+				t.Errorf("UpdateYAMLFile() warnings = %q, want one containing %q", warnings, tt.wantWarning)
+			}
+		})
+	}
+}
+
+func TestUpdateHelmChart_Errors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		files   map[string]string
+		wantErr string
+	}{
+		{name: "missing chart", files: map[string]string{}, wantErr: "reading file"},
+		{name: "no version", files: map[string]string{"Chart.yaml": "name: app\n"}, wantErr: "has no version"},
+		{
+			name:    "version mismatch",
+			files:   map[string]string{"Chart.yaml": "name: app\nversion: 0.9.0\n"},
+			wantErr: "version mismatch",
+		},
+		{
+			name: "missing local dependency",
+			files: map[string]string{
+				"Chart.yaml": "name: app\nversion: 1.0.0\ndependencies:\n  - name: gone\n    version: 1.0.0\n    repository: file://charts/gone\n",
+			},
+			wantErr: "reading dependency gone",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			dir := writeChartFiles(t, tt.files)
+			err := UpdateHelmChart(VersionFileConfig{File: dir, Type: TypeHelm}, "1.0.0", "1.1.0")
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("UpdateHelmChart() error = %v, want to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLockDigest(t *testing.T) {
+	t.Parallel()
+
+	deps := []*chartDependency{
+		{Name: "api", Version: "1.1.0", Repository: "file://charts/api", Condition: "api.enabled",
+			ImportValues: []any{"defaults", map[string]any{"child": "a", "parent": "b"}}},
+		{Name: "redis", Version: "~17.0.0", Repository: "https://charts.example.com", Alias: "cache", Tags: []string{"db"}},
+	}
+	locked := []*chartDependency{
+		{Name: "api", Version: "1.1.0", Repository: "file://charts/api"},
+		{Name: "redis", Version: "17.0.4", Repository: "https://charts.example.com"},
+	}
+
+	// The JSON encoding helm hashes, with its field names, order and omitted empty fields
+	encoded := `[[{"name":"api","version":"1.1.0","repository":"file://charts/api","condition":"api.enabled",` +
+		`"import-values":["defaults",{"child":"a","parent":"b"}]},` +
+		`{"name":"redis","version":"~17.0.0","repository":"https://charts.example.com","tags":["db"],"alias":"cache"}],` +
+		`[{"name":"api","version":"1.1.0","repository":"file://charts/api"},` +
+		`{"name":"redis","version":"17.0.4","repository":"https://charts.example.com"}]]`
+	sum := sha256.Sum256([]byte(encoded))
+
+	got, err := lockDigest(deps, locked)
+	if err != nil {
+		t.Fatalf("lockDigest() error = %v", err)
+	}
+	if want := "sha256:" + hex.EncodeToString(sum[:]); got != want {
+		t.Errorf("lockDigest() = %s, want %s", got, want)
+	}
+}
+
+func TestVersionFileConfig_ModifiedFiles(t *testing.T) {
+	t.Parallel()
+
+	dir := writeChartFiles(t, map[string]string{
+		"platform/Chart.yaml":            "name: platform\nversion: 1.0.0\n",
+		"platform/Chart.lock":            "digest: sha256:00\n",
+		"platform/charts/api/Chart.yaml": "name: api\nversion: 1.0.0\n",
+	})
+
+	got := VersionFileConfig{File: filepath.Join(dir, "platform/charts/api"), Type: TypeHelm}.ModifiedFiles("1.0.0", "1.1.0")
+	want := []string{
+		filepath.Join(dir, "platform/charts/api/Chart.yaml"),
+		filepath.Join(dir, "platform/Chart.yaml"),
+		filepath.Join(dir, "platform/Chart.lock"),
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ModifiedFiles() = %v, want %v", got, want)
+	}
+
+	if got := (VersionFileConfig{File: "values.yaml", Path: "image.tag"}).ModifiedFiles("1.0.0", "1.1.0"); !reflect.DeepEqual(got, []string{"values.yaml"}) {
+		t.Errorf("ModifiedFiles() = %v, want [values.yaml]", got)
+	}
+}
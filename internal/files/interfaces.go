@@ -14,6 +14,11 @@
 
 package files
 
+import (
+	"context"
+	"path/filepath"
+)
+
 // VersionReader reads version information from files.
 type VersionReader interface {
 	// ReadVersion reads the version from the specified path.
@@ -30,6 +35,14 @@ type VersionWriter interface {
 type YAMLUpdater interface {
 	// UpdateYAMLFile updates a specific path in a YAML file with a new version.
 	UpdateYAMLFile(cfg VersionFileConfig, currentVersion, newVersion string) error
+	// UpdateYAMLFiles updates several entries, returning one error per entry (nil if updated).
+	UpdateYAMLFiles(cfgs []VersionFileConfig, currentVersion, newVersion string) []error
+}
+
+// DigestResolver resolves container image references to digests.
+type DigestResolver interface {
+	// Digest returns the manifest digest (sha256:...) of the image reference.
+	Digest(ctx context.Context, ref string) (string, error)
 }
 
 // DefaultVersionReader is the default implementation of VersionReader.
@@ -49,9 +62,75 @@ func (*DefaultVersionWriter) WriteVersion(path, version string) error {
 }
 
 // DefaultYAMLUpdater is the default implementation of YAMLUpdater.
-type DefaultYAMLUpdater struct{}
+type DefaultYAMLUpdater struct {
+	// Digests resolves image digests for entries with PinDigest (optional).
+	Digests DigestResolver
+	// Warn reports non-fatal problems, such as values YAML reads as numbers (optional).
+	Warn func(msg string)
+}
+
+// UpdateYAMLFile updates a specific path in a YAML file with a new version,
+// the whole chart for entries of type TypeHelm, an image for TypeKustomize, the
+// module's major version for TypeGoMod, a Go constant for TypeGo, an XML element
+// for TypeXML, a key for TypeKeyValue, or marked regions for TypeMarker.
+func (u *DefaultYAMLUpdater) UpdateYAMLFile(cfg VersionFileConfig, currentVersion, newVersion string) error {
+	switch cfg.Type {
+	case TypeHelm:
+		return updateHelmChart(cfg, currentVersion, newVersion, u.Warn)
+	case TypeGoMod:
+		return UpdateGoModule(cfg, currentVersion, newVersion)
+	case TypeGo:
+		return UpdateGoConstant(cfg, currentVersion, newVersion)
+	case TypeXML:
+		return UpdateXMLFile(cfg, currentVersion, newVersion)
+	case TypeKeyValue:
+		return UpdateKeyValueFile(cfg, currentVersion, newVersion)
+	case TypeMarker:
+		return UpdateMarkedVersions(cfg, currentVersion, newVersion)
+	case TypeKustomize:
+		return updateKustomizeImage(cfg, currentVersion, newVersion, u.Digests, u.Warn)
+	default:
+		return updateYAMLFile(cfg, currentVersion, newVersion, u.Digests, u.Warn)
+	}
+}
+
+// UpdateYAMLFiles updates several entries in order, returning one error per entry (nil
+// if updated). The YAML entries for the same file are applied together when the first
+// of them is reached, so the file is parsed and written once and edits that overlap are
+// reported as conflicts. Entries of other types are updated one by one. Once all are
+// updated, the local dependencies of every chart are synced again, so that a chart
+// depending on a sibling chart listed after it picks up the sibling's new version.
+func (u *DefaultYAMLUpdater) UpdateYAMLFiles(cfgs []VersionFileConfig, currentVersion, newVersion string) []error {
+	byFile := make(map[string][]int)
+	for i, cfg := range cfgs {
+		if cfg.Type == "" || cfg.Type == TypeYAML {
+			name := filepath.Clean(cfg.File)
+			byFile[name] = append(byFile[name], i)
+		}
+	}
+
+	errs := make([]error, len(cfgs))
+	done := make([]bool, len(cfgs))
+	for i, cfg := range cfgs {
+		if done[i] {
+			continue
+		}
+		indexes, ok := byFile[filepath.Clean(cfg.File)]
+		if !ok || (cfg.Type != "" && cfg.Type != TypeYAML) {
+			errs[i] = u.UpdateYAMLFile(cfg, currentVersion, newVersion)
+			continue
+		}
+
+		group := make([]VersionFileConfig, len(indexes))
+		for j, index := range indexes {
+			group[j] = cfgs[index]
+			done[index] = true
+		}
+		for j, err := range updateYAMLEntries(group, currentVersion, newVersion, u.Digests, u.Warn) {
+			errs[indexes[j]] = err
+		}
+	}
 
-// UpdateYAMLFile updates a specific path in a YAML file with a new version.
-func (*DefaultYAMLUpdater) UpdateYAMLFile(cfg VersionFileConfig, currentVersion, newVersion string) error {
-	return UpdateYAMLFile(cfg, currentVersion, newVersion)
+	resyncChartDependencies(cfgs, errs)
+	return errs
 }
@@ -0,0 +1,245 @@
+// Copyright 2025 Stacklok, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package files
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// keyValueEntry is a key of a key/value file with the offsets of its value.
+type keyValueEntry struct {
+	section, key string
+	// start and end delimit the value, inside quotes if it is quoted.
+	start, end int
+	// multiline is set for values continued on following lines.
+	multiline bool
+}
+
+// path returns the section.key path of the entry.
+func (e keyValueEntry) path() string {
+	if e.section == "" {
+		return e.key
+	}
+	return e.section + "." + e.key
+}
+
+// UpdateKeyValueFile updates the value of a key in a key/value file such as a
+// .properties, .env or INI file. cfg.Path is the key, or section.key for a key under an
+// INI [section]. Keys are separated from values by = or :, and .env lines may start with
+// export. In a .properties file, keys follow java.util.Properties: whitespace separates
+// them too, and an escaped character such as \: or \= is part of the key. A quoted value
+// keeps its quotes, and an unquoted value ends before an inline comment. Values continue
+// on the next line after a trailing backslash or, within a section, on lines indented
+// deeper than the key; in those, only an embedded current version is replaced. The value
+// is checked and replaced as described on VersionFileConfig.
+func UpdateKeyValueFile(cfg VersionFileConfig, currentVersion, newVersion string) error {
+	data, err := os.ReadFile(cfg.File)
+	if err != nil {
+		return fmt.Errorf("reading file %s: %w", cfg.File, err)
+	}
+
+	var matches []keyValueEntry
+	properties := strings.EqualFold(filepath.Ext(cfg.File), ".properties")
+	for _, e := range parseKeyValues(data, properties) {
+		if e.path() == cfg.Path {
+			matches = append(matches, e)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return fmt.Errorf("key %s not found in %s", cfg.Path, cfg.File)
+	case 1:
+	default:
+		return fmt.Errorf("key %s is defined %d times in %s", cfg.Path, len(matches), cfg.File)
+	}
+	e := matches[0]
+
+	value := string(data[e.start:e.end])
+	if e.multiline && cfg.Bump == "" {
+		current, err := cfg.formatVersion(currentVersion)
+		if err != nil {
+			return err
+		}
+		if !strings.Contains(value, current) {
+			return fmt.Errorf("value of %s in %s continues on several lines and does not contain %q",
+				cfg.Path, cfg.File, current)
+		}
+	}
+	var newValue string
+	if cfg.Bump != "" {
+		newValue, err = bumpOwnVersion(cfg, value)
+	} else {
+		newValue, err = replacementValue(cfg, value, currentVersion, newVersion)
+	}
+	if err != nil {
+		return err
+	}
+	data = applyEdits(data, []textEdit{{start: e.start, end: e.end, text: newValue}})
+
+	if err := os.WriteFile(cfg.File, data, 0644); err != nil {
+		return fmt.Errorf("writing file %s: %w", cfg.File, err)
+	}
+	return nil
+}
+
+// parseKeyValues returns the keys of a key/value file in order. Lines that are neither
+// comments, section headers nor key/value pairs are ignored. With properties, keys are
+// read as java.util.Properties reads them.
+func parseKeyValues(data []byte, properties bool) []keyValueEntry {
+	var entries []keyValueEntry
+	var section string
+	// last is the entry that lines indented deeper than its key, lastIndent, continue
+	// within a section, as in configparser
+	last, lastIndent := -1, 0
+
+	lines := bytes.SplitAfter(data, []byte("\n"))
+	offset := 0
+	for i := 0; i < len(lines); i++ {
+		start := offset
+		line := trimLineEnd(string(lines[i]))
+		offset += len(lines[i])
+
+		trimmed := strings.TrimLeft(line, " \t")
+		indent := len(line) - len(trimmed)
+		switch {
+		case trimmed == "":
+			last = -1
+			continue
+		case strings.ContainsAny(trimmed[:1], "#;!"):
+			continue
+		case indent > lastIndent && section != "" && last >= 0:
+			entries[last].end = start + len(strings.TrimRight(line, " \t"))
+			entries[last].multiline = true
+			continue
+		case trimmed[0] == '[':
+			if end := strings.IndexByte(trimmed, ']'); end > 0 {
+				section = strings.TrimSpace(trimmed[1:end])
+			}
+			last = -1
+			continue
+		}
+
+		e, ok := parseKeyValueLine(line, start, properties)
+		if !ok {
+			last = -1
+			continue
+		}
+		e.section = section
+		// A trailing backslash continues the value on the next line
+		for continued(string(data[e.start:e.end])) && i+1 < len(lines) {
+			i++
+			e.end = offset + len(strings.TrimRight(trimLineEnd(string(lines[i])), " \t"))
+			e.multiline = true
+			offset += len(lines[i])
+		}
+		entries = append(entries, e)
+		last, lastIndent = len(entries)-1, indent
+	}
+	return entries
+}
+
+// parseKeyValueLine parses a key = value line starting at offset start.
+func parseKeyValueLine(line string, start int, properties bool) (keyValueEntry, bool) {
+	var key string
+	var valueStart int
+	if properties {
+		key, valueStart = propertiesKey(line)
+	} else {
+		sep := strings.IndexAny(line, "=:")
+		if sep < 0 {
+			return keyValueEntry{}, false
+		}
+		key = strings.TrimSpace(line[:sep])
+		key = strings.TrimSpace(strings.TrimPrefix(key, "export "))
+		valueStart = sep + 1
+	}
+	if key == "" {
+		return keyValueEntry{}, false
+	}
+
+	for valueStart < len(line) && (line[valueStart] == ' ' || line[valueStart] == '\t') {
+		valueStart++
+	}
+	value := line[valueStart:]
+
+	e := keyValueEntry{key: key, start: start + valueStart}
+	if len(value) > 1 && (value[0] == '"' || value[0] == '\'') {
+		if closing := strings.IndexByte(value[1:], value[0]); closing >= 0 {
+			e.start++
+			e.end = e.start + closing
+			return e, true
+		}
+	}
+	for _, comment := range []string{" #", "\t#", " ;", "\t;"} {
+		if i := strings.Index(value, comment); i >= 0 {
+			value = value[:i]
+		}
+	}
+	e.end = e.start + len(strings.TrimRight(value, " \t"))
+	return e, true
+}
+
+// propertiesKey returns the unescaped key of a .properties line and the offset after its
+// separator. The key ends at the first unescaped =, : or whitespace, and whitespace
+// around the separator belongs to neither the key nor the value.
+func propertiesKey(line string) (string, int) {
+	var key strings.Builder
+	i := len(line) - len(strings.TrimLeft(line, " \t\f"))
+	for ; i < len(line); i++ {
+		c := line[i]
+		if c == '\\' && i+1 < len(line) {
+			i++
+			switch line[i] {
+			case 't':
+				key.WriteByte('\t')
+			case 'n':
+				key.WriteByte('\n')
+			case 'r':
+				key.WriteByte('\r')
+			case 'f':
+				key.WriteByte('\f')
+			default:
+				key.WriteByte(line[i])
+			}
+			continue
+		}
+		if strings.IndexByte("=: \t\f", c) >= 0 {
+			break
+		}
+		key.WriteByte(c)
+	}
+	for i < len(line) && strings.IndexByte(" \t\f", line[i]) >= 0 {
+		i++
+	}
+	if i < len(line) && (line[i] == '=' || line[i] == ':') {
+		i++
+	}
+	return key.String(), i
+}
+
+// continued reports whether a value ends with an unescaped backslash.
+func continued(value string) bool {
+	trailing := len(value) - len(strings.TrimRight(value, `\`))
+	return trailing%2 == 1
+}
+
+// trimLineEnd removes the line ending from a line.
+func trimLineEnd(line string) string {
+	return strings.TrimRight(line, "\r\n")
+}
@@ -0,0 +1,210 @@
+// Copyright 2025 Stacklok, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package files
+
+import (
+	"strings"
+	"testing"
+)
+
+const gradleProperties = `# Build settings
+org.gradle.jvmargs=-Xmx2g
+version=1.2.3
+description = Service \
+    version 1.2.3 \
+    for acme
+`
+
+const dotEnv = `APP_NAME=app
+export APP_VERSION="v1.2.3"  # quoted
+IMAGE=ghcr.io/acme/app:v1.2.3 # image
+EMPTY=
+`
+
+const setupCfg = `[metadata]
+name = app
+version = 1.2.3
+
+[tool.bumpversion]
+current_version: 1.2.3
+
+[options]
+install_requires =
+    requests
+    app-client==1.2.3
+`
+
+func TestUpdateKeyValueFile(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		pattern string
+		input   string
+		cfg     VersionFileConfig
+		want    string
+		wantErr string
+	}{
+		{
+			name:  "properties",
+			input: gradleProperties,
+			cfg:   VersionFileConfig{Path: "version"},
+			want:  strings.Replace(gradleProperties, "version=1.2.3", "version=1.3.0", 1),
+		},
+		{
+			name:  "properties continuation line",
+			input: gradleProperties,
+			cfg:   VersionFileConfig{Path: "description"},
+			want:  strings.Replace(gradleProperties, "    version 1.2.3", "    version 1.3.0", 1),
+		},
+		{
+			name:    "env quoted with export and prefix",
+			pattern: ".env-*",
+			input:   dotEnv,
+			cfg:     VersionFileConfig{Path: "APP_VERSION", Prefix: "v"},
+			want:    strings.Replace(dotEnv, `"v1.2.3"`, `"v1.3.0"`, 1),
+		},
+		{
+			name:    "env embedded version before comment",
+			pattern: ".env-*",
+			input:   dotEnv,
+			cfg:     VersionFileConfig{Path: "IMAGE", Prefix: "v"},
+			want:    strings.Replace(dotEnv, "app:v1.2.3 # image", "app:v1.3.0 # image", 1),
+		},
+		{
+			name:    "env empty value",
+			pattern: ".env-*",
+			input:   dotEnv,
+			cfg:     VersionFileConfig{Path: "EMPTY"},
+			want:    strings.Replace(dotEnv, "EMPTY=\n", "EMPTY=1.3.0\n", 1),
+		},
+		{
+			name:    "ini section",
+			pattern: "setup-*.cfg",
+			input:   setupCfg,
+			cfg:     VersionFileConfig{Path: "metadata.version"},
+			want:    strings.Replace(setupCfg, "version = 1.2.3", "version = 1.3.0", 1),
+		},
+		{
+			name:    "ini section with dots and colon separator",
+			pattern: "setup-*.cfg",
+			input:   setupCfg,
+			cfg:     VersionFileConfig{Path: "tool.bumpversion.current_version", Bump: "major"},
+			want:    strings.Replace(setupCfg, "current_version: 1.2.3", "current_version: 2.0.0", 1),
+		},
+		{
+			name:    "ini indented continuation",
+			pattern: "setup-*.cfg",
+			input:   setupCfg,
+			cfg:     VersionFileConfig{Path: "options.install_requires"},
+			want:    strings.Replace(setupCfg, "app-client==1.2.3", "app-client==1.3.0", 1),
+		},
+		{
+			name:    "ini indented keys",
+			pattern: "setup-*.cfg",
+			input:   "[metadata]\n  name = app\n  version = 1.2.3\n[options]\n  install_requires =\n    app-client==1.2.3\n",
+			cfg:     VersionFileConfig{Path: "metadata.version"},
+			want:    "[metadata]\n  name = app\n  version = 1.3.0\n[options]\n  install_requires =\n    app-client==1.2.3\n",
+		},
+		{
+			name:    "ini continuation under indented key",
+			pattern: "setup-*.cfg",
+			input:   "[metadata]\n  name = app\n  version = 1.2.3\n[options]\n  install_requires =\n    app-client==1.2.3\n",
+			cfg:     VersionFileConfig{Path: "options.install_requires"},
+			want:    "[metadata]\n  name = app\n  version = 1.2.3\n[options]\n  install_requires =\n    app-client==1.3.0\n",
+		},
+		{
+			name:    "continuation without current version",
+			pattern: "setup-*.cfg",
+			input:   "[options]\nrequires =\n    app\n",
+			cfg:     VersionFileConfig{Path: "options.requires"},
+			wantErr: "continues on several lines",
+		},
+		{
+			name:    "key outside section",
+			pattern: "setup-*.cfg",
+			input:   setupCfg,
+			cfg:     VersionFileConfig{Path: "version"},
+			wantErr: "key version not found",
+		},
+		{
+			name:    "duplicate key",
+			input:   "VERSION=1.2.3\nVERSION=1.2.3\n",
+			cfg:     VersionFileConfig{Path: "VERSION"},
+			wantErr: "defined 2 times",
+		},
+		{
+			name:    "version mismatch",
+			input:   "version=1.2.2\n",
+			cfg:     VersionFileConfig{Path: "version"},
+			wantErr: "version mismatch",
+		},
+		{
+			name:  "properties whitespace separator",
+			input: "name app\nversion   1.2.3\n",
+			cfg:   VersionFileConfig{Path: "version"},
+			want:  "name app\nversion   1.3.0\n",
+		},
+		{
+			name:  "properties escaped separators in key",
+			input: "app\\:version=0.1.0\napp\\=version : 1.2.3\n",
+			cfg:   VersionFileConfig{Path: "app=version"},
+			want:  "app\\:version=0.1.0\napp\\=version : 1.3.0\n",
+		},
+		{
+			name:  "properties escaped colon in key",
+			input: "app\\:version=1.2.3\napp=0.1.0\n",
+			cfg:   VersionFileConfig{Path: "app:version"},
+			want:  "app\\:version=1.3.0\napp=0.1.0\n",
+		},
+		{
+			name:    "escaped key is not split at its separator",
+			input:   "app\\:version=1.2.3\n",
+			cfg:     VersionFileConfig{Path: "app"},
+			wantErr: "key app not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := tt.cfg
+			cfg.Type = TypeKeyValue
+			pattern := tt.pattern
+			if pattern == "" {
+				pattern = "version-*.properties"
+			}
+			cfg.File = createTempFile(t, tt.input, pattern)
+
+			err := (&DefaultYAMLUpdater{}).UpdateYAMLFile(cfg, "1.2.3", "1.3.0")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("UpdateKeyValueFile() error = %v, want to contain %q", err, tt.wantErr)
+				}
+				if got := readTempFile(t, cfg.File); got != tt.input {
+					t.Errorf("file modified on error:\n%s", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("UpdateKeyValueFile() error = %v", err)
+			}
+			if got := readTempFile(t, cfg.File); got != tt.want {
+				t.Errorf("UpdateKeyValueFile() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
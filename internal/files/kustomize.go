@@ -0,0 +1,123 @@
+// Copyright 2025 Stacklok, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package files
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/goccy/go-yaml"
+)
+
+// kustomization holds the fields of a kustomization.yaml that releaseo reads.
+type kustomization struct {
+	Images []kustomizeImage `yaml:"images"`
+}
+
+// kustomizeImage is an entry of the images list of a kustomization.
+type kustomizeImage struct {
+	Name    string `yaml:"name"`
+	NewName string `yaml:"newName"`
+	NewTag  string `yaml:"newTag"`
+	Digest  string `yaml:"digest"`
+}
+
+// UpdateKustomizeImage updates the newTag of the image named cfg.Image in the
+// kustomization cfg.File. The entry is found by name rather than by position, so
+// reordering the images list does not break the configuration. The tag is checked and
+// replaced as described on VersionFileConfig. With cfg.ClearDigest, the entry's
+// digest is removed so that it no longer pins the previous image; with cfg.PinDigest,
+// it is set to the digest of the new tag, which needs a DigestResolver (see DefaultYAMLUpdater).
+func UpdateKustomizeImage(cfg VersionFileConfig, currentVersion, newVersion string) error {
+	return updateKustomizeImage(cfg, currentVersion, newVersion, nil, nil)
+}
+
+// updateKustomizeImage implements UpdateKustomizeImage, resolving digests with digests and
+// reporting warnings to warn (both optional).
+func updateKustomizeImage(
+	cfg VersionFileConfig, currentVersion, newVersion string, digests DigestResolver, warn func(string),
+) error {
+	data, err := os.ReadFile(cfg.File)
+	if err != nil {
+		return fmt.Errorf("reading file %s: %w", cfg.File, err)
+	}
+
+	var k kustomization
+	if err := yaml.Unmarshal(data, &k); err != nil {
+		return fmt.Errorf("parsing %s: %w", cfg.File, err)
+	}
+
+	index := -1
+	for i, image := range k.Images {
+		if image.Name == cfg.Image {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return fmt.Errorf("image %s not found in %s", cfg.Image, cfg.File)
+	}
+	image := k.Images[index]
+	if image.NewTag == "" {
+		return fmt.Errorf("image %s in %s has no newTag to update", cfg.Image, cfg.File)
+	}
+
+	tagCfg := cfg
+	tagCfg.Path = fmt.Sprintf("images[%d].newTag", index)
+	// The tag is read as written, since a tag such as 1.10 decodes as the number 1.1
+	node, tag, err := rawScalar(data, "$."+tagCfg.Path)
+	if err != nil {
+		return fmt.Errorf("reading newTag of image %s in %s: %w", cfg.Image, cfg.File, err)
+	}
+	var newTag string
+	if cfg.Bump != "" {
+		newTag, err = bumpOwnVersion(tagCfg, tag)
+	} else {
+		newTag, err = replacementValue(tagCfg, tag, currentVersion, newVersion)
+	}
+	if err != nil {
+		return err
+	}
+
+	edits := []scalarEdit{{path: "$." + tagCfg.Path, value: quoteRetyped(tagCfg, node, newTag, warn)}}
+	if cfg.PinDigest {
+		if image.Digest == "" {
+			return fmt.Errorf("image %s in %s has no digest to pin; add a digest key to the entry", cfg.Image, cfg.File)
+		}
+		repository := image.NewName
+		if repository == "" {
+			repository = image.Name
+		}
+		digest, err := resolveDigest(digests, imageReference(repository, newTag))
+		if err != nil {
+			return err
+		}
+		edits = append(edits, scalarEdit{path: fmt.Sprintf("$.images[%d].digest", index), value: digest})
+	}
+
+	if data, err = replaceScalars(data, edits); err != nil {
+		return fmt.Errorf("updating image %s in %s: %w", cfg.Image, cfg.File, err)
+	}
+	if cfg.ClearDigest && image.Digest != "" {
+		if data, err = removeKey(data, fmt.Sprintf("$.images[%d].digest", index), "digest"); err != nil {
+			return fmt.Errorf("clearing digest of image %s in %s: %w", cfg.Image, cfg.File, err)
+		}
+	}
+
+	if err := os.WriteFile(cfg.File, data, 0644); err != nil {
+		return fmt.Errorf("writing file %s: %w", cfg.File, err)
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+// Copyright 2025 Stacklok, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package files
+
+import (
+	"strings"
+	"testing"
+)
+
+const kustomizationYAML = `apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+images:
+  - name: ghcr.io/acme/sidecar
+    newTag: v1.0.0
+  - name: ghcr.io/acme/app
+    newName: registry.example.com/acme/app
+    newTag: "v1.0.0" # release tag
+    digest: sha256:aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa
+`
+
+func TestUpdateKustomizeImage(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		input   string
+		cfg     VersionFileConfig
+		want    string
+		wantErr string
+	}{
+		{
+			name:  "by name, keeping digest",
+			input: kustomizationYAML,
+			cfg:   VersionFileConfig{Image: "ghcr.io/acme/app", Prefix: "v"},
+			want:  strings.Replace(kustomizationYAML, `newTag: "v1.0.0"`, `newTag: "v1.1.0"`, 1),
+		},
+		{
+			name:  "clear digest",
+			input: kustomizationYAML,
+			cfg:   VersionFileConfig{Image: "ghcr.io/acme/app", Prefix: "v", ClearDigest: true},
+			want: strings.NewReplacer(
+				`newTag: "v1.0.0"`, `newTag: "v1.1.0"`,
+				"    digest: sha256:aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa\n", "",
+			).Replace(kustomizationYAML),
+		},
+		{
+			name:  "clear digest without one",
+			input: kustomizationYAML,
+			cfg:   VersionFileConfig{Image: "ghcr.io/acme/sidecar", Prefix: "v", ClearDigest: true},
+			want:  strings.Replace(kustomizationYAML, "newTag: v1.0.0", "newTag: v1.1.0", 1),
+		},
+		{
+			name:  "pin digest of the new name",
+			input: kustomizationYAML,
+			cfg:   VersionFileConfig{Image: "ghcr.io/acme/app", Prefix: "v", PinDigest: true},
+			want: strings.NewReplacer(
+				`newTag: "v1.0.0"`, `newTag: "v1.1.0"`,
+				"sha256:aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa",
+				"sha256:bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb",
+			).Replace(kustomizationYAML),
+		},
+		{
+			name:  "numeric tag",
+			input: "images:\n  - name: app\n    newTag: 1.0\n",
+			cfg:   VersionFileConfig{Image: "app", Template: "{{ .Major }}.{{ .Minor }}"},
+			want:  "images:\n  - name: app\n    newTag: 1.1\n",
+		},
+		{
+			name:    "pin digest without digest key",
+			input:   kustomizationYAML,
+			cfg:     VersionFileConfig{Image: "ghcr.io/acme/sidecar", Prefix: "v", PinDigest: true},
+			wantErr: "no digest to pin",
+		},
+		{
+			name:    "pin digest of missing image",
+			input:   "images:\n  - name: app\n    newTag: 1.0.0\n    digest: sha256:abc\n",
+			cfg:     VersionFileConfig{Image: "app", PinDigest: true},
+			wantErr: "is the image pushed",
+		},
+		{
+			name:    "image not found",
+			input:   kustomizationYAML,
+			cfg:     VersionFileConfig{Image: "ghcr.io/acme/other"},
+			wantErr: "not found",
+		},
+		{
+			name:    "no newTag",
+			input:   "images:\n  - name: app\n    digest: sha256:abc\n",
+			cfg:     VersionFileConfig{Image: "app"},
+			wantErr: "no newTag",
+		},
+		{
+			name:    "version mismatch",
+			input:   "images:\n  - name: app\n    newTag: v0.9.0\n",
+			cfg:     VersionFileConfig{Image: "app", Prefix: "v"},
+			wantErr: "version mismatch",
+		},
+		{
+			name:    "digest in flow mapping",
+			input:   "images:\n  - {name: app, newTag: 1.0.0, digest: sha256:abc}\n",
+			cfg:     VersionFileConfig{Image: "app", ClearDigest: true},
+			wantErr: "line of its own",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := tt.cfg
+			cfg.Type = TypeKustomize
+			cfg.File = createTempFile(t, tt.input, "kustomization-*.yaml")
+
+			updater := &DefaultYAMLUpdater{Digests: fakeDigests{
+				"registry.example.com/acme/app:v1.1.0": "sha256:bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb",
+			}}
+			err := updater.UpdateYAMLFile(cfg, "1.0.0", "1.1.0")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("UpdateKustomizeImage() error = %v, want to contain %q", err, tt.wantErr)
+				}
+				if got := readTempFile(t, cfg.File); got != tt.input {
+					t.Errorf("file modified on error:\n%s", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("UpdateKustomizeImage() error = %v", err)
+			}
+			if got := readTempFile(t, cfg.File); got != tt.want {
+				t.Errorf("UpdateKustomizeImage() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpdateKustomizeImage_QuoteNumericTag(t *testing.T) {
+	t.Parallel()
+
+	var warnings []string
+	updater := &DefaultYAMLUpdater{Warn: func(msg string) { warnings = append(warnings, msg) }}
+	cfg := VersionFileConfig{
+		File:     createTempFile(t, "images:\n  - name: app\n    newTag: 1.9\n", "kustomization-*.yaml"),
+		Type:     TypeKustomize,
+		Image:    "app",
+		Template: "{{ .Major }}.{{ .Minor }}",
+		Quote:    true,
+	}
+	if err := updater.UpdateYAMLFile(cfg, "1.9.0", "1.10.0"); err != nil {
+		t.Fatalf("UpdateYAMLFile() error = %v", err)
+	}
+
+	want := "images:\n  - name: app\n    newTag: \"1.10\"\n"
+	if got := readTempFile(t, cfg.File); got != want {
+		t.Errorf("UpdateYAMLFile() =\n%s\nwant\n%s", got, want)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "quoted 1.10") {
+		t.Errorf("UpdateYAMLFile() warnings = %q, want one about quoting 1.10", warnings)
+	}
+}
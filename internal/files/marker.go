@@ -0,0 +1,227 @@
+// Copyright 2025 Stacklok, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package files
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/stacklok/releaseo/internal/glob"
+)
+
+var (
+	// markerBlock matches a region between <!-- releaseo:version --> and <!-- /releaseo:version -->.
+	markerBlock = regexp.MustCompile(`(?s)<!--\s*releaseo:version\s*-->(.*?)<!--\s*/releaseo:version\s*-->`)
+	// markerLine matches a line ending with a # releaseo:version or // releaseo:version comment.
+	markerLine = regexp.MustCompile(`(?m)^(.*?)(?:#|//)[ \t]*releaseo:version[ \t]*\r?$`)
+)
+
+// UpdateMarkedVersions replaces the current version with the new version in the marked
+// regions of the files matching the glob cfg.File, which may use ** for any number of
+// directories. A region lies between <!-- releaseo:version --> and
+// <!-- /releaseo:version -->, or is a line ending with a # releaseo:version or
+// // releaseo:version comment. Every version in a region, written with cfg.Prefix or
+// cfg.Template, must be the current version: a stale one is reported as a mismatch, like
+// embedded versions in YAML values. Nothing is written unless all regions are up to date.
+func UpdateMarkedVersions(cfg VersionFileConfig, currentVersion, newVersion string) error {
+	paths, err := globFiles(cfg.File)
+	if err != nil {
+		return err
+	}
+	if len(paths) == 0 {
+		return fmt.Errorf("no files match %s", cfg.File)
+	}
+
+	versionPattern, err := cfg.versionPattern()
+	if err != nil {
+		return err
+	}
+	current, err := cfg.formatVersion(currentVersion)
+	if err != nil {
+		return err
+	}
+	next, err := cfg.formatVersion(newVersion)
+	if err != nil {
+		return err
+	}
+
+	updated := map[string][]byte{}
+	var regions int
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("reading file %s: %w", path, err)
+		}
+		var edits []textEdit
+		for _, region := range markedRegions(data) {
+			regionEdits, err := markedVersionEdits(data, region, versionPattern, current, next)
+			if err != nil {
+				return fmt.Errorf("%s:%d: %w", path, bytes.Count(data[:region[0]], []byte("\n"))+1, err)
+			}
+			edits = append(edits, regionEdits...)
+			regions++
+		}
+		if len(edits) > 0 {
+			updated[path] = applyEdits(data, edits)
+		}
+	}
+	if regions == 0 {
+		return fmt.Errorf("no releaseo:version markers found in files matching %s", cfg.File)
+	}
+
+	for _, path := range paths {
+		if data, ok := updated[path]; ok {
+			if err := os.WriteFile(path, data, 0644); err != nil {
+				return fmt.Errorf("writing file %s: %w", path, err)
+			}
+		}
+	}
+	return nil
+}
+
+// markedRegions returns the start and end offsets of the marked regions of a file.
+// Overlapping regions, such as a marked line inside a marked block, are merged, so that
+// each version is replaced once.
+func markedRegions(data []byte) [][2]int {
+	var regions [][2]int
+	for _, m := range markerBlock.FindAllSubmatchIndex(data, -1) {
+		regions = append(regions, [2]int{m[2], m[3]})
+	}
+	for _, m := range markerLine.FindAllSubmatchIndex(data, -1) {
+		regions = append(regions, [2]int{m[2], m[3]})
+	}
+	sort.Slice(regions, func(i, j int) bool { return regions[i][0] < regions[j][0] })
+
+	merged := regions[:0]
+	for _, region := range regions {
+		if last := len(merged) - 1; last >= 0 && region[0] < merged[last][1] {
+			merged[last][1] = max(merged[last][1], region[1])
+			continue
+		}
+		merged = append(merged, region)
+	}
+	return merged
+}
+
+// markedVersionEdits returns the edits replacing the current version in a marked region,
+// failing if the region holds no version or a version other than the current one.
+func markedVersionEdits(data []byte, region [2]int, versionPattern *regexp.Regexp, currentVersion, newVersion string) ([]textEdit, error) {
+	var edits []textEdit
+	text := data[region[0]:region[1]]
+	for _, m := range versionPattern.FindAllIndex(text, -1) {
+		start, end := region[0]+m[0], region[0]+m[1]
+		if inDottedNumber(data, start, end) {
+			continue
+		}
+		if found := string(data[start:end]); found != currentVersion {
+			return nil, fmt.Errorf("version mismatch in marked region: expected %q but found %q. "+
+				"This usually means the file was not updated in a previous release. "+
+				"Please manually update the version in this region to %q before running releaseo",
+				currentVersion, found, currentVersion)
+		}
+		edits = append(edits, textEdit{start: start, end: end, text: newVersion})
+	}
+	if len(edits) == 0 {
+		return nil, fmt.Errorf("marked region holds no version")
+	}
+	return edits, nil
+}
+
+// inDottedNumber reports whether data[start:end] is part of a longer dotted sequence of
+// numbers, such as an IP address, rather than a version of its own.
+func inDottedNumber(data []byte, start, end int) bool {
+	return (start > 0 && isVersionByte(data[start-1])) || (end+1 < len(data) && data[end] == '.' && isDigit(data[end+1]))
+}
+
+// isVersionByte reports whether b can precede a version in a longer dotted number.
+func isVersionByte(b byte) bool {
+	return isDigit(b) || b == '.'
+}
+
+// isDigit reports whether b is an ASCII digit.
+func isDigit(b byte) bool {
+	return b >= '0' && b <= '9'
+}
+
+// globFiles returns the regular files matching a glob pattern, in which ** matches any
+// number of directories. Below the literal directories the pattern starts with, hidden
+// directories such as .git and node_modules are not searched.
+func globFiles(pattern string) ([]string, error) {
+	var matches []string
+	slashPattern := filepath.ToSlash(filepath.Clean(pattern))
+	if !strings.Contains(slashPattern, "**") {
+		var err error
+		if matches, err = filepath.Glob(pattern); err != nil {
+			return nil, fmt.Errorf("invalid glob %s: %w", pattern, err)
+		}
+	} else {
+		if _, err := path.Match(slashPattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid glob %s: %w", pattern, err)
+		}
+		root := globRoot(slashPattern)
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				if path != root && (strings.HasPrefix(d.Name(), ".") || d.Name() == "node_modules") {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if glob.Match(slashPattern, filepath.ToSlash(path)) {
+				matches = append(matches, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("matching %s: %w", pattern, err)
+		}
+	}
+
+	files := matches[:0]
+	for _, path := range matches {
+		if info, err := os.Stat(path); err == nil && info.Mode().IsRegular() {
+			files = append(files, path)
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// globRoot returns the directory made of the segments of a slash-separated pattern
+// before its first glob syntax, below which every match lies.
+func globRoot(pattern string) string {
+	segments := strings.Split(pattern, "/")
+	n := 0
+	for n < len(segments)-1 && !strings.ContainsAny(segments[n], `*?[\`) {
+		n++
+	}
+	switch root := strings.Join(segments[:n], "/"); {
+	case n == 0:
+		return "."
+	case root == "":
+		return "/"
+	default:
+		return filepath.FromSlash(root)
+	}
+}
@@ -0,0 +1,199 @@
+// Copyright 2025 Stacklok, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package files
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const markedReadme = `# App
+
+<!-- releaseo:version -->
+` + "```" + `sh
+helm install app oci://ghcr.io/acme/charts/app --version 1.2.3
+curl -LO https://github.com/acme/app/releases/download/v1.2.3/app_1.2.3_linux.tar.gz
+` + "```" + `
+<!-- /releaseo:version -->
+
+Requires Kubernetes 1.28.0 and a node at 10.0.0.1.
+`
+
+func TestUpdateMarkedVersions(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		files   map[string]string
+		pattern string
+		prefix  string
+		want    map[string]string
+		wantErr string
+	}{
+		{
+			name: "block and line markers across a glob",
+			files: map[string]string{
+				"docs/README.md":          markedReadme,
+				"docs/guide/install.md":   "Pull <!-- releaseo:version -->`ghcr.io/acme/app:1.2.3`<!-- /releaseo:version --> now.\n",
+				"docs/guide/unmarked.md":  "Version 0.1.0 is old.\n",
+				"docs/guide/install.yaml": "image: app:1.2.3 # releaseo:version\n",
+			},
+			pattern: "docs/**/*.md",
+			want: map[string]string{
+				"docs/README.md":          strings.ReplaceAll(markedReadme, "1.2.3", "1.3.0"),
+				"docs/guide/install.md":   "Pull <!-- releaseo:version -->`ghcr.io/acme/app:1.3.0`<!-- /releaseo:version --> now.\n",
+				"docs/guide/unmarked.md":  "Version 0.1.0 is old.\n",
+				"docs/guide/install.yaml": "image: app:1.2.3 # releaseo:version\n",
+			},
+		},
+		{
+			name: "line markers",
+			files: map[string]string{
+				"install.sh": "VERSION=v1.2.3 # releaseo:version\nOTHER=v0.1.0\nconst v = \"1.2.3\" // releaseo:version\n",
+			},
+			pattern: "*.sh",
+			want: map[string]string{
+				"install.sh": "VERSION=v1.3.0 # releaseo:version\nOTHER=v0.1.0\nconst v = \"1.3.0\" // releaseo:version\n",
+			},
+		},
+		{
+			name: "prefix narrows the versions",
+			files: map[string]string{
+				"install.sh": "go install example.com/app@v1.2.3 && go version 1.22.0 # releaseo:version\n",
+			},
+			pattern: "install.sh",
+			prefix:  "v",
+			want: map[string]string{
+				"install.sh": "go install example.com/app@v1.3.0 && go version 1.22.0 # releaseo:version\n",
+			},
+		},
+		{
+			name: "stale version",
+			files: map[string]string{
+				"a.md": "<!-- releaseo:version -->1.2.3<!-- /releaseo:version -->\n",
+				"b.md": "<!-- releaseo:version -->\nhelm install --version 1.2.2\n<!-- /releaseo:version -->\n",
+			},
+			pattern: "*.md",
+			wantErr: `b.md:1: version mismatch in marked region: expected "1.2.3" but found "1.2.2"`,
+		},
+		{
+			name:    "region without version",
+			files:   map[string]string{"README.md": "<!-- releaseo:version -->latest<!-- /releaseo:version -->\n"},
+			pattern: "*.md",
+			wantErr: "holds no version",
+		},
+		{
+			name:    "no markers",
+			files:   map[string]string{"README.md": "Version 1.2.3\n"},
+			pattern: "*.md",
+			wantErr: "no releaseo:version markers",
+		},
+		{
+			name:    "no files",
+			files:   map[string]string{"README.md": "Version 1.2.3\n"},
+			pattern: "docs/*.md",
+			wantErr: "no files match",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			dir := writeChartFiles(t, tt.files)
+			cfg := VersionFileConfig{File: filepath.Join(dir, tt.pattern), Type: TypeMarker, Prefix: tt.prefix}
+			err := (&DefaultYAMLUpdater{}).UpdateYAMLFile(cfg, "1.2.3", "1.3.0")
+
+			want := tt.want
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("UpdateMarkedVersions() error = %v, want to contain %q", err, tt.wantErr)
+				}
+				want = tt.files
+			} else if err != nil {
+				t.Fatalf("UpdateMarkedVersions() error = %v", err)
+			}
+			for name, content := range want {
+				if got := readTempFile(t, filepath.Join(dir, name)); got != content {
+					t.Errorf("%s =\n%s\nwant\n%s", name, got, content)
+				}
+			}
+		})
+	}
+}
+
+func TestUpdateMarkedVersions_NestedMarkers(t *testing.T) {
+	t.Parallel()
+
+	input := "<!-- releaseo:version -->\n```sh\nVERSION=1.2.3 # releaseo:version\ncurl -LO app_1.2.3.tar.gz\n```\n<!-- /releaseo:version -->\n"
+	dir := writeChartFiles(t, map[string]string{"README.md": input})
+	cfg := VersionFileConfig{File: filepath.Join(dir, "README.md"), Type: TypeMarker}
+
+	if err := UpdateMarkedVersions(cfg, "1.2.3", "1.2.10"); err != nil {
+		t.Fatalf("UpdateMarkedVersions() error = %v", err)
+	}
+	want := strings.ReplaceAll(input, "1.2.3", "1.2.10")
+	if got := readTempFile(t, cfg.File); got != want {
+		t.Errorf("UpdateMarkedVersions() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestGlobFiles(t *testing.T) {
+	t.Parallel()
+
+	dir := writeChartFiles(t, map[string]string{
+		"README.md":                       "",
+		"docs/index.md":                   "",
+		"docs/a/b/guide.md":               "",
+		"docs/a/notes.txt":                "",
+		"docs/v1/guides/setup/install.md": "",
+		"docs/.cache/page.md":             "",
+		"node_modules/pkg/README.md":      "",
+	})
+
+	tests := []struct {
+		pattern string
+		want    []string
+	}{
+		{pattern: "*.md", want: []string{"README.md"}},
+		{pattern: "docs/**/*.md", want: []string{"docs/a/b/guide.md", "docs/index.md", "docs/v1/guides/setup/install.md"}},
+		{pattern: "**/*.md", want: []string{"README.md", "docs/a/b/guide.md", "docs/index.md", "docs/v1/guides/setup/install.md"}},
+		{pattern: "docs/**/guides/**/*.md", want: []string{"docs/v1/guides/setup/install.md"}},
+		{pattern: "docs/.cache/**/*.md", want: []string{"docs/.cache/page.md"}},
+		{pattern: "docs/**/notes.txt", want: []string{"docs/a/notes.txt"}},
+		{pattern: "docs/*.txt", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := globFiles(filepath.Join(dir, tt.pattern))
+			if err != nil {
+				t.Fatalf("globFiles() error = %v", err)
+			}
+			var rel []string
+			for _, path := range got {
+				r, _ := filepath.Rel(dir, path)
+				rel = append(rel, filepath.ToSlash(r))
+			}
+			if !reflect.DeepEqual(rel, tt.want) {
+				t.Errorf("globFiles(%s) = %v, want %v", tt.pattern, rel, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,161 @@
+// Copyright 2025 Stacklok, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package files
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// pathSegment is a component of a version file path: a mapping key, or a sequence index.
+type pathSegment struct {
+	key     string
+	index   int
+	isIndex bool
+}
+
+// plainYAMLPathKey matches keys that can be written unquoted in a YAML path.
+var plainYAMLPathKey = regexp.MustCompile(`^[^.\[\]'*$\\]+$`)
+
+// parsePath splits a dot-notation path into keys and indices. A key holding dots or
+// other special characters is quoted, either as a segment (labels."helm.sh/chart") or
+// in brackets (annotations["app.kubernetes.io/version"]); single quotes work as well,
+// and a backslash escapes the quote. Brackets also hold sequence indices (containers[0]).
+func parsePath(path string) ([]pathSegment, error) {
+	s := strings.TrimPrefix(path, "$.")
+	var segments []pathSegment
+	expectKey := true
+	for i := 0; i < len(s); {
+		switch {
+		case s[i] == '[':
+			segment, end, err := parseBracket(s, i)
+			if err != nil {
+				return nil, err
+			}
+			segments = append(segments, segment)
+			i = end
+			expectKey = false
+		case s[i] == '.':
+			if expectKey {
+				return nil, fmt.Errorf("empty key at offset %d", i)
+			}
+			i++
+			expectKey = true
+		case !expectKey:
+			return nil, fmt.Errorf("expected '.' or '[' at offset %d", i)
+		case s[i] == '"' || s[i] == '\'':
+			key, end, err := parseQuoted(s, i)
+			if err != nil {
+				return nil, err
+			}
+			segments = append(segments, pathSegment{key: key})
+			i = end
+			expectKey = false
+		default:
+			end := i + strings.IndexAny(s[i:]+".", ".[")
+			segments = append(segments, pathSegment{key: s[i:end]})
+			i = end
+			expectKey = false
+		}
+	}
+	if len(segments) == 0 {
+		return nil, fmt.Errorf("path cannot be empty")
+	}
+	if expectKey {
+		return nil, fmt.Errorf("path cannot end with '.'")
+	}
+	return segments, nil
+}
+
+// parseBracket parses the bracket selector starting at s[start], returning the segment
+// and the offset following it.
+func parseBracket(s string, start int) (pathSegment, int, error) {
+	i := start + 1
+	if i < len(s) && (s[i] == '"' || s[i] == '\'') {
+		key, end, err := parseQuoted(s, i)
+		if err != nil {
+			return pathSegment{}, 0, err
+		}
+		if end >= len(s) || s[end] != ']' {
+			return pathSegment{}, 0, fmt.Errorf("missing ']' at offset %d", end)
+		}
+		return pathSegment{key: key}, end + 1, nil
+	}
+
+	end := strings.IndexByte(s[i:], ']')
+	if end < 0 {
+		return pathSegment{}, 0, fmt.Errorf("missing ']' after offset %d", start)
+	}
+	index, err := strconv.Atoi(s[i : i+end])
+	if err != nil || index < 0 {
+		return pathSegment{}, 0, fmt.Errorf("invalid index %q: brackets hold an index or a quoted key", s[i:i+end])
+	}
+	return pathSegment{index: index, isIndex: true}, i + end + 1, nil
+}
+
+// parseQuoted parses the quoted key starting at s[start], returning the key and the
+// offset following the closing quote.
+func parseQuoted(s string, start int) (string, int, error) {
+	quote := s[start]
+	var sb strings.Builder
+	for i := start + 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			if i+1 < len(s) {
+				i++
+				sb.WriteByte(s[i])
+			}
+		case quote:
+			if sb.Len() == 0 {
+				return "", 0, fmt.Errorf("empty key at offset %d", start)
+			}
+			return sb.String(), i + 1, nil
+		default:
+			sb.WriteByte(s[i])
+		}
+	}
+	return "", 0, fmt.Errorf("unterminated quote at offset %d", start)
+}
+
+// yamlPathString returns the path in the YAML path syntax of goccy/go-yaml, which
+// quotes keys with special characters in single quotes.
+func yamlPathString(segments []pathSegment) string {
+	var sb strings.Builder
+	sb.WriteString("$")
+	for _, segment := range segments {
+		switch {
+		case segment.isIndex:
+			fmt.Fprintf(&sb, "[%d]", segment.index)
+		case plainYAMLPathKey.MatchString(segment.key):
+			sb.WriteString("." + segment.key)
+		default:
+			escaped := strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(segment.key)
+			sb.WriteString(".'" + escaped + "'")
+		}
+	}
+	return sb.String()
+}
+
+// lastKey returns the last mapping key of the path.
+func lastKey(segments []pathSegment) string {
+	for i := len(segments) - 1; i >= 0; i-- {
+		if !segments[i].isIndex {
+			return segments[i].key
+		}
+	}
+	return ""
+}
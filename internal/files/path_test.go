@@ -0,0 +1,121 @@
+// Copyright 2025 Stacklok, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package files
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParsePath(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		path    string
+		want    []pathSegment
+		wantErr string
+	}{
+		{path: "image.tag", want: []pathSegment{{key: "image"}, {key: "tag"}}},
+		{path: "$.image.tag", want: []pathSegment{{key: "image"}, {key: "tag"}}},
+		{
+			path: "spec.containers[0].image",
+			want: []pathSegment{{key: "spec"}, {key: "containers"}, {index: 0, isIndex: true}, {key: "image"}},
+		},
+		{
+			path: `metadata.annotations["app.kubernetes.io/version"]`,
+			want: []pathSegment{{key: "metadata"}, {key: "annotations"}, {key: "app.kubernetes.io/version"}},
+		},
+		{
+			path: `labels."helm.sh/chart".value`,
+			want: []pathSegment{{key: "labels"}, {key: "helm.sh/chart"}, {key: "value"}},
+		},
+		{
+			path: `matrix[1]['it\'s'][2]`,
+			want: []pathSegment{{key: "matrix"}, {index: 1, isIndex: true}, {key: "it's"}, {index: 2, isIndex: true}},
+		},
+		{path: "", wantErr: "cannot be empty"},
+		{path: "a..b", wantErr: "empty key"},
+		{path: "a.", wantErr: "cannot end with '.'"},
+		{path: `a."b`, wantErr: "unterminated quote"},
+		{path: `a["b"`, wantErr: "missing ']'"},
+		{path: "a[-1]", wantErr: "invalid index"},
+		{path: `a."b"c`, wantErr: "expected '.' or '['"},
+		{path: `a[""]`, wantErr: "empty key"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := parsePath(tt.path)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("parsePath() error = %v, want to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parsePath() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parsePath() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpdateYAMLFile_QuotedKeys(t *testing.T) {
+	t.Parallel()
+
+	input := `metadata:
+  labels:
+    helm.sh/chart: app-1.0.0
+    app.kubernetes.io/version: "1.0.0"
+  annotations:
+    "app.kubernetes.io/version": 1.0.0 # kept in sync
+    version: 1.0.0
+`
+	tests := []struct {
+		name string
+		path string
+		want string
+	}{
+		{
+			name: "bracket",
+			path: `metadata.annotations["app.kubernetes.io/version"]`,
+			want: strings.Replace(input, `"app.kubernetes.io/version": 1.0.0`, `"app.kubernetes.io/version": 1.1.0`, 1),
+		},
+		{
+			name: "quoted segment",
+			path: `metadata.labels."helm.sh/chart"`,
+			want: strings.Replace(input, "app-1.0.0", "app-1.1.0", 1),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := VersionFileConfig{File: createTempFile(t, input, "values-*.yaml"), Path: tt.path}
+			if err := UpdateYAMLFile(cfg, "1.0.0", "1.1.0"); err != nil {
+				t.Fatalf("UpdateYAMLFile() error = %v", err)
+			}
+			if got := readTempFile(t, cfg.File); got != tt.want {
+				t.Errorf("UpdateYAMLFile() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,232 @@
+// Copyright 2025 Stacklok, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package files
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/stacklok/releaseo/internal/version"
+)
+
+// Supported version source types.
+const (
+	// SourceFile reads the version from a plain VERSION file.
+	SourceFile = "file"
+	// SourceYAML reads the version from a path in a YAML file (e.g., Chart.yaml version).
+	SourceYAML = "yaml"
+	// SourceJSON reads the version from a path in a JSON file (e.g., package.json version).
+	SourceJSON = "json"
+	// SourceGitTag reads the version from the highest semver git tag.
+	SourceGitTag = "git-tag"
+)
+
+// VersionSourceConfig defines where the current version is read from.
+type VersionSourceConfig struct {
+	Type      string `json:"type"`
+	File      string `json:"file,omitempty"`
+	Path      string `json:"path,omitempty"`
+	TagPrefix string `json:"tag_prefix,omitempty"`
+}
+
+// IsFile returns true if the source is a plain VERSION file (the default).
+func (c VersionSourceConfig) IsFile() bool {
+	return c.Type == "" || c.Type == SourceFile
+}
+
+// Validate checks that the fields required by the source type are set.
+func (c VersionSourceConfig) Validate() error {
+	switch c.Type {
+	case "", SourceFile, SourceGitTag:
+		return nil
+	case SourceYAML, SourceJSON:
+		if c.File == "" {
+			return fmt.Errorf("file is required for %s version source", c.Type)
+		}
+		if c.Path == "" {
+			return fmt.Errorf("path is required for %s version source", c.Type)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown version source type %q (expected %s, %s, %s or %s)",
+			c.Type, SourceFile, SourceYAML, SourceJSON, SourceGitTag)
+	}
+}
+
+// NewVersionReader returns the VersionReader implementation for the given source.
+func NewVersionReader(cfg VersionSourceConfig) (VersionReader, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	switch cfg.Type {
+	case SourceYAML:
+		return &YAMLVersionReader{Path: cfg.Path}, nil
+	case SourceJSON:
+		return &JSONVersionReader{Path: cfg.Path}, nil
+	case SourceGitTag:
+		return &GitTagVersionReader{Prefix: cfg.TagPrefix}, nil
+	default:
+		return &DefaultVersionReader{}, nil
+	}
+}
+
+// YAMLVersionReader reads the version from a dot-notation path in a YAML file.
+type YAMLVersionReader struct {
+	Path string
+}
+
+// ReadVersion reads the version stored at r.Path in the YAML file at path.
+func (r *YAMLVersionReader) ReadVersion(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("reading file %s: %w", path, err)
+	}
+
+	yamlPath, err := convertToYAMLPath(r.Path)
+	if err != nil {
+		return "", fmt.Errorf("invalid path %s: %w", r.Path, err)
+	}
+
+	value, err := readScalar(data, yamlPath)
+	if err != nil {
+		return "", fmt.Errorf("path %s not found in %s: %w", r.Path, path, err)
+	}
+
+	return nonEmptyVersion(value, path, r.Path)
+}
+
+// JSONVersionReader reads the version from a dot-notation path in a JSON file.
+type JSONVersionReader struct {
+	Path string
+}
+
+// ReadVersion reads the version stored at r.Path in the JSON file at path.
+func (r *JSONVersionReader) ReadVersion(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("reading file %s: %w", path, err)
+	}
+
+	var doc any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return "", fmt.Errorf("parsing JSON file %s: %w", path, err)
+	}
+
+	value, err := lookupJSONPath(doc, r.Path)
+	if err != nil {
+		return "", fmt.Errorf("path %s not found in %s: %w", r.Path, path, err)
+	}
+
+	return nonEmptyVersion(value, path, r.Path)
+}
+
+// lookupJSONPath walks a decoded JSON document following a dot-notation path
+// and returns the string value found at the end of it.
+func lookupJSONPath(doc any, path string) (string, error) {
+	segments, err := parsePath(path)
+	if err != nil {
+		return "", err
+	}
+
+	current := doc
+	var parent string
+	for _, segment := range segments {
+		if segment.isIndex {
+			arr, ok := current.([]any)
+			if !ok || segment.index >= len(arr) {
+				return "", fmt.Errorf("index %d out of range for %q", segment.index, parent)
+			}
+			current = arr[segment.index]
+			continue
+		}
+
+		obj, ok := current.(map[string]any)
+		if !ok {
+			return "", fmt.Errorf("%q is not an object", segment.key)
+		}
+		if current, ok = obj[segment.key]; !ok {
+			return "", fmt.Errorf("key %q does not exist", segment.key)
+		}
+		parent = segment.key
+	}
+
+	value, ok := current.(string)
+	if !ok {
+		return "", fmt.Errorf("value is not a string")
+	}
+	return value, nil
+}
+
+// GitTagVersionReader reads the version from the highest semver tag in the repository.
+// Only tags starting with Prefix are considered; a leading "v" is accepted either way.
+// Tags that do not parse as MAJOR.MINOR.PATCH (e.g., pre-releases) are ignored.
+type GitTagVersionReader struct {
+	Prefix string
+
+	// listTags returns the tags matching the given glob. Overridden in tests.
+	listTags func(pattern string) ([]string, error)
+}
+
+// ReadVersion returns the highest semver tag as MAJOR.MINOR.PATCH. The path argument is unused.
+func (r *GitTagVersionReader) ReadVersion(_ string) (string, error) {
+	list := r.listTags
+	if list == nil {
+		list = gitListTags
+	}
+
+	tags, err := list(r.Prefix + "*")
+	if err != nil {
+		return "", err
+	}
+
+	var best *version.Version
+	for _, tag := range tags {
+		v, err := version.Parse(strings.TrimPrefix(tag, r.Prefix))
+		if err != nil {
+			continue
+		}
+		if best == nil || v.Compare(best) > 0 {
+			best = v
+		}
+	}
+
+	if best == nil {
+		return "", fmt.Errorf("no semver tags found matching %q (is the checkout fetching tags?)", r.Prefix+"*")
+	}
+	return best.String(), nil
+}
+
+// gitListTags lists tags in the current repository matching the given glob.
+func gitListTags(pattern string) ([]string, error) {
+	cmd := exec.Command("git", "tag", "--list", pattern)
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("running git tag: %w", err)
+	}
+	return strings.Fields(string(output)), nil
+}
+
+// nonEmptyVersion trims the value and returns an error if it is empty.
+func nonEmptyVersion(value, file, path string) (string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", fmt.Errorf("version at path %s in %s is empty", path, file)
+	}
+	return value, nil
+}
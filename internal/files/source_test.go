@@ -0,0 +1,275 @@
+// Copyright 2025 Stacklok, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package files
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestVersionSourceConfig_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		cfg     VersionSourceConfig
+		wantErr bool
+	}{
+		{name: "empty defaults to file", cfg: VersionSourceConfig{}},
+		{name: "file", cfg: VersionSourceConfig{Type: SourceFile}},
+		{name: "git tag", cfg: VersionSourceConfig{Type: SourceGitTag, TagPrefix: "v"}},
+		{name: "yaml", cfg: VersionSourceConfig{Type: SourceYAML, File: "Chart.yaml", Path: "version"}},
+		{name: "json", cfg: VersionSourceConfig{Type: SourceJSON, File: "package.json", Path: "version"}},
+		{name: "yaml missing file", cfg: VersionSourceConfig{Type: SourceYAML, Path: "version"}, wantErr: true},
+		{name: "json missing path", cfg: VersionSourceConfig{Type: SourceJSON, File: "package.json"}, wantErr: true},
+		{name: "unknown type", cfg: VersionSourceConfig{Type: "toml"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := tt.cfg.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestYAMLVersionReader(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		content string
+		path    string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "chart version",
+			content: "apiVersion: v2\nname: app\nversion: 1.2.3\nappVersion: \"0.9.0\"\n",
+			path:    "version",
+			want:    "1.2.3",
+		},
+		{
+			name:    "nested quoted value",
+			content: "image:\n  tag: \"v0.9.0\"\n",
+			path:    "image.tag",
+			want:    "v0.9.0",
+		},
+		{
+			name:    "missing path",
+			content: "name: app\n",
+			path:    "version",
+			wantErr: true,
+		},
+		{
+			name:    "empty value",
+			content: "version: \"\"\n",
+			path:    "version",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			tmpPath := createTempFile(t, tt.content, "source-*.yaml")
+
+			got, err := (&YAMLVersionReader{Path: tt.path}).ReadVersion(tmpPath)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ReadVersion() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ReadVersion() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJSONVersionReader(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		content string
+		path    string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "package.json version",
+			content: `{"name": "app", "version": "1.2.3", "private": true}`,
+			path:    "version",
+			want:    "1.2.3",
+		},
+		{
+			name:    "nested path with index",
+			content: `{"packages": [{"name": "a", "meta": {"version": "2.0.0"}}]}`,
+			path:    "packages[0].meta.version",
+			want:    "2.0.0",
+		},
+		{
+			name:    "key with dots",
+			content: `{"dependencies": {"@acme/app.core": "3.1.0"}}`,
+			path:    `dependencies["@acme/app.core"]`,
+			want:    "3.1.0",
+		},
+		{
+			name:    "index out of range",
+			content: `{"packages": []}`,
+			path:    "packages[0].version",
+			wantErr: true,
+		},
+		{
+			name:    "non-string value",
+			content: `{"version": 1}`,
+			path:    "version",
+			wantErr: true,
+		},
+		{
+			name:    "missing key",
+			content: `{"name": "app"}`,
+			path:    "version",
+			wantErr: true,
+		},
+		{
+			name:    "invalid JSON",
+			content: `{"version": `,
+			path:    "version",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			tmpPath := createTempFile(t, tt.content, "source-*.json")
+
+			got, err := (&JSONVersionReader{Path: tt.path}).ReadVersion(tmpPath)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ReadVersion() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ReadVersion() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGitTagVersionReader(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		prefix      string
+		tags        []string
+		listErr     error
+		wantPattern string
+		want        string
+		wantErr     bool
+	}{
+		{
+			name:        "highest semver wins over lexical order",
+			tags:        []string{"v1.9.0", "v1.10.0", "v1.2.3"},
+			wantPattern: "*",
+			want:        "1.10.0",
+		},
+		{
+			name:        "non-semver and pre-release tags are ignored",
+			tags:        []string{"latest", "v2.0.0-rc.1", "v1.4.0"},
+			wantPattern: "*",
+			want:        "1.4.0",
+		},
+		{
+			name:        "component prefix",
+			prefix:      "operator/v",
+			tags:        []string{"operator/v0.3.1", "operator/v0.3.0"},
+			wantPattern: "operator/v*",
+			want:        "0.3.1",
+		},
+		{
+			name:        "no tags",
+			wantPattern: "*",
+			wantErr:     true,
+		},
+		{
+			name:        "git error",
+			listErr:     errors.New("not a git repository"),
+			wantPattern: "*",
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var gotPattern string
+			r := &GitTagVersionReader{
+				Prefix: tt.prefix,
+				listTags: func(pattern string) ([]string, error) {
+					gotPattern = pattern
+					return tt.tags, tt.listErr
+				},
+			}
+
+			got, err := r.ReadVersion("")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ReadVersion() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ReadVersion() = %q, want %q", got, tt.want)
+			}
+			if gotPattern != tt.wantPattern {
+				t.Errorf("listTags pattern = %q, want %q", gotPattern, tt.wantPattern)
+			}
+		})
+	}
+}
+
+func TestNewVersionReader(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		cfg     VersionSourceConfig
+		want    VersionReader
+		wantErr bool
+	}{
+		{name: "default", cfg: VersionSourceConfig{}, want: &DefaultVersionReader{}},
+		{name: "yaml", cfg: VersionSourceConfig{Type: SourceYAML, File: "Chart.yaml", Path: "version"}, want: &YAMLVersionReader{}},
+		{name: "json", cfg: VersionSourceConfig{Type: SourceJSON, File: "package.json", Path: "version"}, want: &JSONVersionReader{}},
+		{name: "git tag", cfg: VersionSourceConfig{Type: SourceGitTag}, want: &GitTagVersionReader{}},
+		{name: "invalid", cfg: VersionSourceConfig{Type: "nope"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := NewVersionReader(tt.cfg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewVersionReader() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if gotType, wantType := fmt.Sprintf("%T", got), fmt.Sprintf("%T", tt.want); gotType != wantType {
+				t.Errorf("NewVersionReader() = %s, want %s", gotType, wantType)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright 2025 Stacklok, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package files
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+)
+
+// semverPattern matches a MAJOR.MINOR.PATCH version with an optional prerelease.
+const semverPattern = `\d+\.\d+\.\d+(?:-[a-zA-Z0-9.]+)?`
+
+// VersionData is the data a version file Template is evaluated with.
+type VersionData struct {
+	Major      string
+	Minor      string
+	Patch      string
+	Prerelease string
+	// Full is the whole version, such as 1.4.0 or 1.4.0-rc.1.
+	Full string
+}
+
+// newVersionData splits a version into its components.
+func newVersionData(v string) VersionData {
+	core, prerelease, _ := strings.Cut(v, "-")
+	parts := append(strings.SplitN(core, ".", 3), "", "", "")
+	return VersionData{Major: parts[0], Minor: parts[1], Patch: parts[2], Prerelease: prerelease, Full: v}
+}
+
+// placeholders stand for the components of any version when a template is turned into a
+// pattern; they are left alone by regexp.QuoteMeta.
+var placeholders = strings.NewReplacer(
+	"\x00major\x00", `\d+`,
+	"\x00minor\x00", `\d+`,
+	"\x00patch\x00", `\d+`,
+	"\x00prerelease\x00", `[a-zA-Z0-9.]+`,
+	"\x00full\x00", semverPattern,
+)
+
+// parseTemplate parses the entry's Template.
+func (c VersionFileConfig) parseTemplate() (*template.Template, error) {
+	tmpl, err := template.New("version").Option("missingkey=error").Parse(c.Template)
+	if err != nil {
+		return nil, fmt.Errorf("parsing template for %s: %w", c.File, err)
+	}
+	return tmpl, nil
+}
+
+// formatVersion returns the version as written to the entry's value: rendered with
+// Template if set, or with Prefix prepended.
+func (c VersionFileConfig) formatVersion(v string) (string, error) {
+	if c.Template == "" {
+		return c.Prefix + v, nil
+	}
+	tmpl, err := c.parseTemplate()
+	if err != nil {
+		return "", err
+	}
+	return renderVersion(tmpl, newVersionData(v))
+}
+
+// versionPattern returns a regular expression matching any version written the way the
+// entry writes versions, so that a stale version can be told from an unrelated value.
+func (c VersionFileConfig) versionPattern() (*regexp.Regexp, error) {
+	if c.Template == "" {
+		return regexp.MustCompile(regexp.QuoteMeta(c.Prefix) + semverPattern), nil
+	}
+	tmpl, err := c.parseTemplate()
+	if err != nil {
+		return nil, err
+	}
+
+	// Render once with and once without a prerelease, for templates that test it
+	data := VersionData{
+		Major:      "\x00major\x00",
+		Minor:      "\x00minor\x00",
+		Patch:      "\x00patch\x00",
+		Prerelease: "\x00prerelease\x00",
+		Full:       "\x00full\x00",
+	}
+	var alternatives []string
+	for _, prerelease := range []string{data.Prerelease, ""} {
+		data.Prerelease = prerelease
+		rendered, err := renderVersion(tmpl, data)
+		if err != nil {
+			return nil, err
+		}
+		alternatives = append(alternatives, placeholders.Replace(regexp.QuoteMeta(rendered)))
+	}
+	return regexp.Compile(strings.Join(alternatives, "|"))
+}
+
+// renderVersion executes a version template.
+func renderVersion(tmpl *template.Template, data VersionData) (string, error) {
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, data); err != nil {
+		return "", fmt.Errorf("evaluating template: %w", err)
+	}
+	return sb.String(), nil
+}
@@ -0,0 +1,192 @@
+// Copyright 2025 Stacklok, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package files
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestVersionFileConfig_FormatVersion(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		cfg      VersionFileConfig
+		version  string
+		want     string
+		wantErr  string
+		matches  []string
+		rejected []string
+	}{
+		{
+			name:     "prefix",
+			cfg:      VersionFileConfig{Prefix: "v"},
+			version:  "1.4.0",
+			want:     "v1.4.0",
+			matches:  []string{"v1.3.9", "app:v2.0.0-rc.1"},
+			rejected: []string{"1.3.9"},
+		},
+		{
+			name:     "docs channel",
+			cfg:      VersionFileConfig{Template: "{{ .Major }}.{{ .Minor }}"},
+			version:  "1.4.0",
+			want:     "1.4",
+			matches:  []string{"1.3", "docs/1.3/"},
+			rejected: []string{"latest"},
+		},
+		{
+			name:    "Go module suffix",
+			cfg:     VersionFileConfig{Template: "v{{ .Major }}"},
+			version: "2.1.0",
+			want:    "v2",
+			matches: []string{"v1"},
+		},
+		{
+			name:     "chart suffix",
+			cfg:      VersionFileConfig{Template: "{{ .Full }}-chart"},
+			version:  "1.4.0",
+			want:     "1.4.0-chart",
+			matches:  []string{"1.3.0-chart"},
+			rejected: []string{"1.3.0"},
+		},
+		{
+			name:     "optional prerelease",
+			cfg:      VersionFileConfig{Template: "v{{ .Major }}.{{ .Minor }}.{{ .Patch }}{{ if .Prerelease }}-{{ .Prerelease }}{{ end }}"},
+			version:  "1.4.0-rc.1",
+			want:     "v1.4.0-rc.1",
+			matches:  []string{"v1.3.0", "v1.3.0-beta.2"},
+			rejected: []string{"1.3.0"},
+		},
+		{
+			name:    "unknown field",
+			cfg:     VersionFileConfig{Template: "{{ .Build }}"},
+			version: "1.4.0",
+			wantErr: "evaluating template",
+		},
+		{
+			name:    "invalid template",
+			cfg:     VersionFileConfig{Template: "{{ .Major "},
+			version: "1.4.0",
+			wantErr: "parsing template",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := tt.cfg.formatVersion(tt.version)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("formatVersion() error = %v, want to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("formatVersion() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("formatVersion() = %q, want %q", got, tt.want)
+			}
+
+			pattern, err := tt.cfg.versionPattern()
+			if err != nil {
+				t.Fatalf("versionPattern() error = %v", err)
+			}
+			for _, value := range tt.matches {
+				if !pattern.MatchString(value) {
+					t.Errorf("versionPattern() %s does not match %q", pattern, value)
+				}
+			}
+			for _, value := range tt.rejected {
+				if pattern.MatchString(value) {
+					t.Errorf("versionPattern() %s matches %q", pattern, value)
+				}
+			}
+		})
+	}
+}
+
+func TestUpdateYAMLFile_Template(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		input   string
+		cfg     VersionFileConfig
+		want    string
+		wantErr string
+	}{
+		{
+			name:  "docs channel",
+			input: "docs:\n  channel: \"1.3\"\n",
+			cfg:   VersionFileConfig{Path: "docs.channel", Template: "{{ .Major }}.{{ .Minor }}"},
+			want:  "docs:\n  channel: \"1.4\"\n",
+		},
+		{
+			name:  "embedded in a URL",
+			input: "docs: https://docs.example.com/1.3/install\n",
+			cfg:   VersionFileConfig{Path: "docs", Template: "{{ .Major }}.{{ .Minor }}"},
+			want:  "docs: https://docs.example.com/1.4/install\n",
+		},
+		{
+			name:  "placeholder replaced",
+			input: "tag: latest\n",
+			cfg:   VersionFileConfig{Path: "tag", Template: "v{{ .Full }}"},
+			want:  "tag: v1.4.0\n",
+		},
+		{
+			name:    "stale derived value",
+			input:   "docs:\n  channel: \"1.2\"\n",
+			cfg:     VersionFileConfig{Path: "docs.channel", Template: "{{ .Major }}.{{ .Minor }}"},
+			wantErr: `expected to find "1.3" but found "1.2"`,
+		},
+		{
+			name:    "rendered version within a longer number",
+			input:   "channel: \"10\"\n",
+			cfg:     VersionFileConfig{Path: "channel", Template: "{{ .Major }}"},
+			wantErr: `expected to find "1" but found "10"`,
+		},
+		{
+			name:    "rendered version prefixing a stale one",
+			input:   "docs: https://docs.example.com/1.30/install\n",
+			cfg:     VersionFileConfig{Path: "docs", Template: "{{ .Major }}.{{ .Minor }}"},
+			wantErr: `expected to find "1.3" but found "1.30"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := tt.cfg
+			cfg.File = createTempFile(t, tt.input, "values-*.yaml")
+			err := UpdateYAMLFile(cfg, "1.3.2", "1.4.0")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("UpdateYAMLFile() error = %v, want to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("UpdateYAMLFile() error = %v", err)
+			}
+			if got := readTempFile(t, cfg.File); got != tt.want {
+				t.Errorf("UpdateYAMLFile() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
@@ -15,6 +15,8 @@
 package files
 
 import (
+	"context"
+	"fmt"
 	"os"
 	"testing"
 )
@@ -48,3 +50,13 @@ func readTempFile(t *testing.T, path string) string {
 	}
 	return string(content)
 }
+
+// fakeDigests resolves image references from a map, standing in for a registry.
+type fakeDigests map[string]string
+
+func (f fakeDigests) Digest(_ context.Context, ref string) (string, error) {
+	if digest, ok := f[ref]; ok {
+		return digest, nil
+	}
+	return "", fmt.Errorf("%s: image not found", ref)
+}
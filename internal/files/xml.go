@@ -0,0 +1,224 @@
+// Copyright 2025 Stacklok, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package files
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// xmlStep matches a step of an XML path: a name, optionally prefixed, and an optional
+// predicate selecting by position ([2]) or by the text of a child ([artifactId='app']).
+var xmlStep = regexp.MustCompile(`^(?:[\w.-]+:)?([\w.-]+)(?:\[(?:(\d+)|([\w.-]+)\s*=\s*(?:'([^']*)'|"([^"]*)"))\])?$`)
+
+// xmlNode is an element of an XML document, with the offsets of its content.
+type xmlNode struct {
+	name     string
+	children []*xmlNode
+	text     string
+	// start and end delimit the content between the start and end tags.
+	start, end int
+	// plain is false when the content holds entities, CDATA sections or comments,
+	// so that the text cannot be edited in place.
+	plain       bool
+	selfClosing bool
+}
+
+// UpdateXMLFile updates the text of the element at cfg.Path in the XML file cfg.File,
+// such as a Maven pom.xml, a .csproj or an Info.plist. The path is slash-separated
+// (/project/version) and steps match the local name of elements, so a default
+// namespace such as Maven's needs no declaration. A step can select by position
+// (dependency[2]) or by the text of a child (dependency[artifactId='app']). In a plist
+// dict, a step can name a key to select the value that follows it. The text is checked
+// and replaced as described on VersionFileConfig, and only the text is rewritten,
+// preserving comments, attributes and whitespace.
+func UpdateXMLFile(cfg VersionFileConfig, currentVersion, newVersion string) error {
+	data, err := os.ReadFile(cfg.File)
+	if err != nil {
+		return fmt.Errorf("reading file %s: %w", cfg.File, err)
+	}
+
+	root, err := parseXML(data)
+	if err != nil {
+		return fmt.Errorf("parsing %s: %w", cfg.File, err)
+	}
+	node, err := findXMLNode(root, cfg.Path)
+	if err != nil {
+		return fmt.Errorf("path %s not found in %s: %w", cfg.Path, cfg.File, err)
+	}
+	switch {
+	case len(node.children) > 0:
+		return fmt.Errorf("element at path %s in %s has child elements", cfg.Path, cfg.File)
+	case node.selfClosing:
+		return fmt.Errorf("element at path %s in %s is empty; add a value to update", cfg.Path, cfg.File)
+	case !node.plain:
+		return fmt.Errorf("element at path %s in %s holds entities, CDATA or comments, which cannot be updated in place", cfg.Path, cfg.File)
+	}
+
+	value := strings.TrimSpace(node.text)
+	var newValue string
+	if cfg.Bump != "" {
+		newValue, err = bumpOwnVersion(cfg, value)
+	} else {
+		newValue, err = replacementValue(cfg, value, currentVersion, newVersion)
+	}
+	if err != nil {
+		return err
+	}
+
+	var escaped bytes.Buffer
+	if err := xml.EscapeText(&escaped, []byte(newValue)); err != nil {
+		return fmt.Errorf("escaping %q: %w", newValue, err)
+	}
+	start := node.start + strings.Index(node.text, value)
+	data = applyEdits(data, []textEdit{{start: start, end: start + len(value), text: escaped.String()}})
+
+	if err := os.WriteFile(cfg.File, data, 0644); err != nil {
+		return fmt.Errorf("writing file %s: %w", cfg.File, err)
+	}
+	return nil
+}
+
+// parseXML returns the document element of an XML document.
+func parseXML(data []byte) (*xmlNode, error) {
+	d := xml.NewDecoder(bytes.NewReader(data))
+	var root *xmlNode
+	var stack []*xmlNode
+	for {
+		offset := int(d.InputOffset())
+		tok, err := d.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var top *xmlNode
+		if len(stack) > 0 {
+			top = stack[len(stack)-1]
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			n := &xmlNode{name: t.Name.Local, start: int(d.InputOffset()), plain: true}
+			n.selfClosing = bytes.HasSuffix(data[:n.start], []byte("/>"))
+			if top == nil {
+				root = n
+			} else {
+				top.children = append(top.children, n)
+			}
+			stack = append(stack, n)
+		case xml.EndElement:
+			top.end = offset
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			if top != nil {
+				top.text += string(t)
+				top.plain = top.plain && string(data[offset:d.InputOffset()]) == string(t)
+			}
+		case xml.Comment, xml.ProcInst:
+			if top != nil {
+				top.plain = false
+			}
+		}
+	}
+	if root == nil {
+		return nil, fmt.Errorf("no root element")
+	}
+	return root, nil
+}
+
+// findXMLNode returns the single element the path selects, starting at the document element.
+func findXMLNode(root *xmlNode, path string) (*xmlNode, error) {
+	steps := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	candidates := []*xmlNode{{children: []*xmlNode{root}}}
+	for _, step := range steps {
+		m := xmlStep.FindStringSubmatch(step)
+		if m == nil {
+			return nil, fmt.Errorf("invalid step %q", step)
+		}
+		var next []*xmlNode
+		for _, n := range candidates {
+			next = append(next, n.childrenNamed(m[1])...)
+		}
+		next, err := filterXMLNodes(next, m)
+		if err != nil {
+			return nil, err
+		}
+		if len(next) == 0 {
+			return nil, fmt.Errorf("no element matches %q", step)
+		}
+		candidates = next
+	}
+	if len(candidates) > 1 {
+		return nil, fmt.Errorf("%d elements match; add a predicate such as [1]", len(candidates))
+	}
+	return candidates[0], nil
+}
+
+// childrenNamed returns the child elements with the local name. In a plist dict, the
+// name can also be a key, whose value is the element following it.
+func (n *xmlNode) childrenNamed(name string) []*xmlNode {
+	var matches []*xmlNode
+	for _, child := range n.children {
+		if child.name == name {
+			matches = append(matches, child)
+		}
+	}
+	if len(matches) == 0 && n.name == "dict" {
+		for i, child := range n.children {
+			if child.name == "key" && strings.TrimSpace(child.text) == name && i+1 < len(n.children) {
+				matches = append(matches, n.children[i+1])
+			}
+		}
+	}
+	return matches
+}
+
+// filterXMLNodes applies the predicate of a step, as matched by xmlStep, to the nodes.
+func filterXMLNodes(nodes []*xmlNode, step []string) ([]*xmlNode, error) {
+	if step[2] != "" {
+		position, err := strconv.Atoi(step[2])
+		if err != nil || position < 1 {
+			return nil, fmt.Errorf("invalid position in %q; positions start at 1", step[0])
+		}
+		if position > len(nodes) {
+			return nil, nil
+		}
+		return nodes[position-1 : position], nil
+	}
+	if step[3] == "" {
+		return nodes, nil
+	}
+
+	want := step[4] + step[5]
+	var filtered []*xmlNode
+	for _, n := range nodes {
+		for _, child := range n.childrenNamed(step[3]) {
+			if strings.TrimSpace(child.text) == want {
+				filtered = append(filtered, n)
+				break
+			}
+		}
+	}
+	return filtered, nil
+}
@@ -0,0 +1,179 @@
+// Copyright 2025 Stacklok, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package files
+
+import (
+	"strings"
+	"testing"
+)
+
+const pomXML = `<?xml version="1.0" encoding="UTF-8"?>
+<!-- Service build -->
+<project xmlns="http://maven.apache.org/POM/4.0.0"
+         xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance">
+  <modelVersion>4.0.0</modelVersion>
+  <parent>
+    <groupId>com.acme</groupId>
+    <version>3.1.0</version>
+  </parent>
+  <artifactId>app</artifactId>
+  <version>1.2.3</version> <!-- released by releaseo -->
+  <properties>
+    <revision>
+      1.2.3
+    </revision>
+  </properties>
+  <dependencies>
+    <dependency>
+      <artifactId>client</artifactId>
+      <version>v1.2.3</version>
+    </dependency>
+    <dependency>
+      <artifactId>other</artifactId>
+      <version>0.1.0</version>
+    </dependency>
+  </dependencies>
+</project>
+`
+
+const infoPlist = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>CFBundleName</key>
+	<string>App</string>
+	<key>CFBundleShortVersionString</key>
+	<string>1.2.3</string>
+</dict>
+</plist>
+`
+
+func TestUpdateXMLFile(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		input   string
+		cfg     VersionFileConfig
+		want    string
+		wantErr string
+	}{
+		{
+			name:  "maven project version",
+			input: pomXML,
+			cfg:   VersionFileConfig{Path: "/project/version"},
+			want:  strings.Replace(pomXML, "<version>1.2.3</version> <!--", "<version>1.3.0</version> <!--", 1),
+		},
+		{
+			name:  "whitespace around text is kept",
+			input: pomXML,
+			cfg:   VersionFileConfig{Path: "project/properties/revision"},
+			want:  strings.Replace(pomXML, "      1.2.3\n", "      1.3.0\n", 1),
+		},
+		{
+			name:  "child value predicate with prefix",
+			input: pomXML,
+			cfg:   VersionFileConfig{Path: "/project/dependencies/dependency[artifactId='client']/version", Prefix: "v"},
+			want:  strings.Replace(pomXML, "v1.2.3", "v1.3.0", 1),
+		},
+		{
+			name:  "namespace prefix in path and position",
+			input: pomXML,
+			cfg:   VersionFileConfig{Path: "/m:project/m:dependencies/m:dependency[2]/m:version", Bump: "patch"},
+			want:  strings.Replace(pomXML, "0.1.0", "0.1.1", 1),
+		},
+		{
+			name:  "csproj",
+			input: "\ufeff<Project Sdk=\"Microsoft.NET.Sdk\">\r\n  <PropertyGroup>\r\n    <Version>1.2.3</Version>\r\n  </PropertyGroup>\r\n</Project>\r\n",
+			cfg:   VersionFileConfig{Path: "/Project/PropertyGroup/Version"},
+			want:  "\ufeff<Project Sdk=\"Microsoft.NET.Sdk\">\r\n  <PropertyGroup>\r\n    <Version>1.3.0</Version>\r\n  </PropertyGroup>\r\n</Project>\r\n",
+		},
+		{
+			name:  "plist key",
+			input: infoPlist,
+			cfg:   VersionFileConfig{Path: "/plist/dict/CFBundleShortVersionString"},
+			want:  strings.Replace(infoPlist, "<string>1.2.3</string>", "<string>1.3.0</string>", 1),
+		},
+		{
+			name:    "version mismatch",
+			input:   pomXML,
+			cfg:     VersionFileConfig{Path: "/project/parent/version"},
+			wantErr: "version mismatch",
+		},
+		{
+			name:    "ambiguous path",
+			input:   pomXML,
+			cfg:     VersionFileConfig{Path: "/project/dependencies/dependency/version"},
+			wantErr: "2 elements match",
+		},
+		{
+			name:    "not found",
+			input:   pomXML,
+			cfg:     VersionFileConfig{Path: "/project/build/version"},
+			wantErr: `no element matches "build"`,
+		},
+		{
+			name:    "element with children",
+			input:   pomXML,
+			cfg:     VersionFileConfig{Path: "/project/parent"},
+			wantErr: "has child elements",
+		},
+		{
+			name:    "self-closing element",
+			input:   "<project><version/></project>",
+			cfg:     VersionFileConfig{Path: "/project/version"},
+			wantErr: "is empty",
+		},
+		{
+			name:    "CDATA",
+			input:   "<project><version><![CDATA[1.2.3]]></version></project>",
+			cfg:     VersionFileConfig{Path: "/project/version"},
+			wantErr: "cannot be updated in place",
+		},
+		{
+			name:    "invalid XML",
+			input:   "<project><version>1.2.3</project>",
+			cfg:     VersionFileConfig{Path: "/project/version"},
+			wantErr: "parsing",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := tt.cfg
+			cfg.Type = TypeXML
+			cfg.File = createTempFile(t, tt.input, "version-*.xml")
+
+			err := (&DefaultYAMLUpdater{}).UpdateYAMLFile(cfg, "1.2.3", "1.3.0")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("UpdateXMLFile() error = %v, want to contain %q", err, tt.wantErr)
+				}
+				if got := readTempFile(t, cfg.File); got != tt.input {
+					t.Errorf("file modified on error:\n%s", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("UpdateXMLFile() error = %v", err)
+			}
+			if got := readTempFile(t, cfg.File); got != tt.want {
+				t.Errorf("UpdateXMLFile() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
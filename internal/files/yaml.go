@@ -16,87 +16,557 @@ package files
 
 import (
 	"bytes"
+	"context"
 	"fmt"
+	"go/token"
 	"os"
 	"regexp"
+	"slices"
+	"strconv"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/goccy/go-yaml"
+	"github.com/goccy/go-yaml/ast"
+	"github.com/goccy/go-yaml/parser"
+	yamltoken "github.com/goccy/go-yaml/token"
+
+	"github.com/stacklok/releaseo/internal/version"
+)
+
+// Version file types.
+const (
+	// TypeYAML updates a single path in a YAML or JSON file. It is the default.
+	TypeYAML = "yaml"
+	// TypeHelm updates a Helm chart directory: its version, appVersion and local dependencies.
+	TypeHelm = "helm"
+	// TypeKustomize updates the newTag of an image in a kustomization.yaml.
+	TypeKustomize = "kustomize"
+	// TypeGoMod moves a Go module to a new major version: the /vN suffix of its module
+	// path and the imports of its packages.
+	TypeGoMod = "gomod"
+	// TypeGo updates a package-level string constant or variable in a Go source file.
+	TypeGo = "go"
+	// TypeXML updates the text of an element in an XML file, such as a pom.xml or .csproj.
+	TypeXML = "xml"
+	// TypeKeyValue updates a key in a .properties, .env or INI file.
+	TypeKeyValue = "keyvalue"
+	// TypeMarker updates the versions in regions marked by releaseo:version comments.
+	TypeMarker = "marker"
 )
 
+// BumpSame gives a version file its own version stream, bumped like the app version.
+const BumpSame = "same"
+
 // VersionFileConfig defines a YAML file and the path to update with the new version.
+// With Type TypeHelm, File is a chart directory and Path is not used. With Type
+// TypeKustomize, File is a kustomization and Image selects the entry to update. With
+// Type TypeGoMod, File is a go.mod and only major bumps to v2 and above change anything.
+// With Type TypeGo, File is a Go source file and Path names the constant or variable.
+// With Type TypeXML, Path is a slash-separated element path (see UpdateXMLFile), and
+// with Type TypeKeyValue, a key or section.key. With Type TypeMarker, File is a glob
+// and Path is not used.
+//
+// Whatever the type, a value is checked and replaced the same way: the current version,
+// written with Prefix or rendered with Template, is replaced where it appears in the
+// value, and a value holding any other version is reported as a mismatch. A Go literal
+// is stricter and must hold the current version alone. With Bump, the version the value
+// holds is bumped instead.
 type VersionFileConfig struct {
 	File   string `json:"file"`
 	Path   string `json:"path"`
 	Prefix string `json:"prefix,omitempty"`
+	Type   string `json:"type,omitempty"`
+	// Bump gives the value its own version stream: the version it holds is bumped by
+	// this bump type (or the app's, with BumpSame) instead of being set to the app version.
+	// For Helm charts it applies to the chart version; appVersion follows the app.
+	Bump string `json:"bump,omitempty"`
+	// Template derives the written value from the version, in place of Prefix, such as
+	// {{ .Major }}.{{ .Minor }} for a docs channel. It is evaluated with VersionData, for
+	// the new version and for the current one, which the value is expected to hold.
+	Template string `json:"template,omitempty"`
+	// Image is the name of the kustomize image entry to update. For YAML values holding
+	// only a tag, it is the repository used to resolve the digest for DigestPath.
+	Image string `json:"image,omitempty"`
+	// ClearDigest removes the digest of the kustomize image entry, which would otherwise
+	// keep pinning the previous image.
+	ClearDigest bool `json:"clear_digest,omitempty"`
+	// PinDigest resolves the new image tag to its digest in the registry. The digest is
+	// appended to the value (repo:tag@sha256:...), or written to DigestPath if set; for
+	// kustomize it is written to the image entry's digest.
+	PinDigest bool `json:"pin_digest,omitempty"`
+	// DigestPath is the dot-notation path of an existing key receiving the digest.
+	DigestPath string `json:"digest_path,omitempty"`
+	// Quote writes the new value double-quoted when, written plain, YAML would read it as
+	// a number or another type than a string, such as 1.10 as the float 1.1.
+	Quote bool `json:"quote,omitempty"`
+}
+
+// WithAppBump returns the configuration with BumpSame resolved to the app's bump type.
+func (c VersionFileConfig) WithAppBump(appBump string) VersionFileConfig {
+	if c.Bump == BumpSame {
+		c.Bump = appBump
+	}
+	return c
+}
+
+// Validate checks that the configuration is complete for its type.
+func (c VersionFileConfig) Validate() error {
+	if c.File == "" {
+		return fmt.Errorf("file is required")
+	}
+	if err := c.validateType(); err != nil {
+		return err
+	}
+	if err := c.validateDigest(); err != nil {
+		return err
+	}
+	if err := c.validateTemplate(); err != nil {
+		return err
+	}
+	if c.Quote && c.Type != "" && c.Type != TypeYAML && c.Type != TypeHelm && c.Type != TypeKustomize {
+		return fmt.Errorf("quote cannot be used with type %s; it only applies to YAML values", c.Type)
+	}
+	if c.Bump != "" && c.Bump != BumpSame {
+		if _, err := (&version.Version{}).Bump(c.Bump); err != nil {
+			return fmt.Errorf("invalid bump for %s: %w", c.File, err)
+		}
+	}
+	return nil
+}
+
+// validateType checks the fields that select what is updated.
+func (c VersionFileConfig) validateType() error {
+	switch c.Type {
+	case "", TypeYAML, TypeXML, TypeKeyValue:
+		if c.Path == "" {
+			return fmt.Errorf("path is required for %s", c.File)
+		}
+	case TypeHelm:
+		if c.Path != "" {
+			return fmt.Errorf("path cannot be used with type %s; the chart's version and appVersion are updated", TypeHelm)
+		}
+	case TypeKustomize:
+		if c.Image == "" {
+			return fmt.Errorf("image is required with type %s", TypeKustomize)
+		}
+		if c.Path != "" {
+			return fmt.Errorf("path cannot be used with type %s; the image is selected by name", TypeKustomize)
+		}
+	case TypeGoMod:
+		if c.Path != "" || c.Prefix != "" || c.Bump != "" || c.Template != "" {
+			return fmt.Errorf("path, prefix, template and bump cannot be used with type %s; the module follows the app's major version", TypeGoMod)
+		}
+	case TypeMarker:
+		if c.Path != "" || c.Bump != "" {
+			return fmt.Errorf("path and bump cannot be used with type %s; marked regions hold the app version", TypeMarker)
+		}
+	case TypeGo:
+		if !token.IsIdentifier(c.Path) {
+			return fmt.Errorf("path must name a constant or variable with type %s, got %q", TypeGo, c.Path)
+		}
+	default:
+		return fmt.Errorf("unknown type %q (expected %s, %s, %s, %s, %s, %s, %s or %s)",
+			c.Type, TypeYAML, TypeHelm, TypeKustomize, TypeGoMod, TypeGo, TypeXML, TypeKeyValue, TypeMarker)
+	}
+	return nil
+}
+
+// validateDigest checks the digest options against the type.
+func (c VersionFileConfig) validateDigest() error {
+	switch {
+	case c.DigestPath != "" && !c.PinDigest:
+		return fmt.Errorf("digest_path requires pin_digest")
+	case c.ClearDigest && c.Type != TypeKustomize:
+		return fmt.Errorf("clear_digest can only be used with type %s", TypeKustomize)
+	case c.ClearDigest && c.PinDigest:
+		return fmt.Errorf("clear_digest and pin_digest are mutually exclusive")
+	case c.PinDigest && c.Type != "" && c.Type != TypeYAML && c.Type != TypeKustomize:
+		return fmt.Errorf("pin_digest cannot be used with type %s", c.Type)
+	case c.DigestPath != "" && c.Type == TypeKustomize:
+		return fmt.Errorf("digest_path cannot be used with type %s; the image entry's digest is set", TypeKustomize)
+	case c.Image != "" && c.Type != TypeKustomize && c.DigestPath == "":
+		return fmt.Errorf("image can only be used with type %s, or with digest_path to name the repository of a tag", TypeKustomize)
+	}
+	return nil
+}
+
+// validateTemplate checks that the template renders a version and is not combined with
+// options it replaces.
+func (c VersionFileConfig) validateTemplate() error {
+	if c.Template == "" {
+		return nil
+	}
+	switch {
+	case c.Prefix != "":
+		return fmt.Errorf("prefix and template are mutually exclusive; put the prefix in the template")
+	case c.Bump != "" && c.Type != TypeHelm:
+		return fmt.Errorf("bump cannot be used with template; the bumped version would have to be read back from the value")
+	}
+	if _, err := c.formatVersion("1.2.3-rc.1"); err != nil {
+		return err
+	}
+	_, err := c.versionPattern()
+	return err
+}
+
+// Location describes what the entry updates, for messages.
+func (c VersionFileConfig) Location() string {
+	switch c.Type {
+	case TypeHelm:
+		return "chart " + c.File
+	case TypeKustomize:
+		return fmt.Sprintf("image %s in %s", c.Image, c.File)
+	case TypeGoMod:
+		return "Go module " + c.File
+	case TypeGo:
+		return fmt.Sprintf("%s in %s", c.Path, c.File)
+	case TypeMarker:
+		return "marked versions in " + c.File
+	default:
+		return fmt.Sprintf("%s at path %s", c.File, c.Path)
+	}
+}
+
+// Summary describes the entry in Markdown, as listed in the default PR body: the file,
+// followed by the path or what else of it is updated.
+func (c VersionFileConfig) Summary() string {
+	switch c.Type {
+	case TypeHelm:
+		return fmt.Sprintf("`%s` (helm chart)", c.File)
+	case TypeKustomize:
+		return fmt.Sprintf("`%s` (image: `%s`)", c.File, c.Image)
+	case TypeGoMod:
+		return fmt.Sprintf("`%s` (Go module)", c.File)
+	case TypeMarker:
+		return fmt.Sprintf("`%s` (marked versions)", c.File)
+	default:
+		return fmt.Sprintf("`%s` (path: `%s`)", c.File, c.Path)
+	}
+}
+
+// ModifiedFiles returns the files an update of this entry from currentVersion to
+// newVersion may change.
+func (c VersionFileConfig) ModifiedFiles(currentVersion, newVersion string) []string {
+	switch c.Type {
+	case TypeHelm:
+		return helmChartFiles(c.File)
+	case TypeGoMod:
+		return goModuleFiles(c.File, currentVersion, newVersion)
+	case TypeMarker:
+		files, _ := globFiles(c.File)
+		return files
+	default:
+		return []string{c.File}
+	}
 }
 
 // UpdateYAMLFile updates a specific path in a YAML file with a new version.
 // It uses surgical text replacement to preserve the original file formatting.
 // The currentVersion is used to find embedded versions within larger values (e.g., image tags).
+// Entries with PinDigest need a DigestResolver, and warnings about values YAML would
+// read as another type are only reported through DefaultYAMLUpdater.
 func UpdateYAMLFile(cfg VersionFileConfig, currentVersion, newVersion string) error {
-	// Read the file content
-	data, err := os.ReadFile(cfg.File)
+	return updateYAMLFile(cfg, currentVersion, newVersion, nil, nil)
+}
+
+// updateYAMLFile implements UpdateYAMLFile, resolving digests with digests and reporting
+// warnings to warn (both optional).
+func updateYAMLFile(cfg VersionFileConfig, currentVersion, newVersion string, digests DigestResolver, warn func(string)) error {
+	return updateYAMLEntries([]VersionFileConfig{cfg}, currentVersion, newVersion, digests, warn)[0]
+}
+
+// yamlUpdate is the change an entry makes to a YAML file: edits of the text of the
+// original document, and for a value that could not be located by position, a
+// replacement keyed by the last key of its path, applied after the edits.
+type yamlUpdate struct {
+	edits []textEdit
+	// fallbackErr is the error locating the value, reported if the keyed replacement fails.
+	fallbackErr                  error
+	key, oldValue, fallbackValue string
+}
+
+// updateYAMLEntries updates entries that all edit the same YAML file, returning one error
+// per entry. The file is read and parsed once, every value is located in the original
+// document, and all edits are written at once. An entry whose edits overlap those of an
+// earlier entry fails; identical edits, such as two paths through aliases of one anchor,
+// are applied once. The entries that succeed are written even if others fail.
+func updateYAMLEntries(
+	cfgs []VersionFileConfig, currentVersion, newVersion string, digests DigestResolver, warn func(string),
+) []error {
+	errs := make([]error, len(cfgs))
+	failAll := func(err error) []error {
+		for i := range errs {
+			errs[i] = err
+		}
+		return errs
+	}
+
+	filename := cfgs[0].File
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return failAll(fmt.Errorf("reading file %s: %w", filename, err))
+	}
+	file, err := parser.ParseBytes(data, 0)
 	if err != nil {
-		return fmt.Errorf("reading file %s: %w", cfg.File, err)
+		return failAll(fmt.Errorf("parsing YAML in %s: %w", filename, err))
+	}
+
+	var edits []textEdit
+	owners := make(map[textEdit]int)
+	updates := make([]*yamlUpdate, len(cfgs))
+	for i, cfg := range cfgs {
+		update, err := planYAMLUpdate(cfg, data, file, currentVersion, newVersion, digests, warn)
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+		if errs[i] = conflictingEdit(cfgs, owners, edits, update.edits); errs[i] != nil {
+			continue
+		}
+		for _, edit := range update.edits {
+			if _, dup := owners[edit]; !dup {
+				owners[edit] = i
+				edits = append(edits, edit)
+			}
+		}
+		updates[i] = update
 	}
 
+	newData := applyEdits(data, edits)
+	for i, update := range updates {
+		if update == nil || update.fallbackErr == nil {
+			continue
+		}
+		fallback, err := surgicalReplace(newData, update.key, update.oldValue, update.fallbackValue)
+		if err != nil {
+			errs[i], updates[i] = fmt.Errorf("replacing value at path %s: %w", cfgs[i].Path, update.fallbackErr), nil
+			continue
+		}
+		newData = fallback
+	}
+
+	if !slices.ContainsFunc(updates, func(u *yamlUpdate) bool { return u != nil }) {
+		return errs
+	}
+	if err := os.WriteFile(filename, newData, 0644); err != nil {
+		return failAll(fmt.Errorf("writing file %s: %w", filename, err))
+	}
+	return errs
+}
+
+// conflictingEdit returns an error if one of edits overlaps, without being identical to,
+// an edit already accepted from the entry owners records for it.
+func conflictingEdit(cfgs []VersionFileConfig, owners map[textEdit]int, accepted, edits []textEdit) error {
+	for _, edit := range edits {
+		for _, other := range accepted {
+			if edit != other && edit.start < other.end && other.start < edit.end {
+				return fmt.Errorf("writing %s conflicts with the edit for path %s", edit.text, cfgs[owners[other]].Path)
+			}
+		}
+	}
+	return nil
+}
+
+// planYAMLUpdate returns the change cfg makes to data, parsed as file. Digests are resolved
+// here, so a missing image leaves the file untouched.
+func planYAMLUpdate(
+	cfg VersionFileConfig, data []byte, file *ast.File, currentVersion, newVersion string,
+	digests DigestResolver, warn func(string),
+) (*yamlUpdate, error) {
 	// Convert dot notation path to YAML path format
 	yamlPath, err := convertToYAMLPath(cfg.Path)
 	if err != nil {
-		return fmt.Errorf("invalid path %s: %w", cfg.Path, err)
+		return nil, fmt.Errorf("invalid path %s: %w", cfg.Path, err)
 	}
 
-	// Create the path and read current value to validate it exists
-	path, err := yaml.PathString(yamlPath)
+	// Read the current value, as written, to validate it exists
+	node, err := fileScalarNode(file, yamlPath)
 	if err != nil {
-		return fmt.Errorf("creating path %s: %w", yamlPath, err)
+		return nil, fmt.Errorf("path %s not found in %s: %w", cfg.Path, cfg.File, err)
+	}
+	valueAtPath, err := scalarValue(node)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s at path %s: %w", cfg.File, cfg.Path, err)
 	}
 
-	var valueAtPath string
-	if err := path.Read(bytes.NewReader(data), &valueAtPath); err != nil {
-		return fmt.Errorf("path %s not found in %s: %w", cfg.Path, cfg.File, err)
+	var newValue string
+	if cfg.Bump != "" {
+		newValue, err = bumpOwnVersion(cfg, valueAtPath)
+	} else {
+		newValue, err = replacementValue(cfg, valueAtPath, currentVersion, newVersion)
+	}
+	if err != nil {
+		return nil, err
 	}
 
-	// Build the old and new version strings with prefix
-	oldVersionStr := cfg.Prefix + currentVersion
-	newVersionStr := cfg.Prefix + newVersion
+	var digest string
+	if cfg.PinDigest {
+		if digest, err = resolveDigest(digests, imageReference(cfg.Image, newValue)); err != nil {
+			return nil, err
+		}
+		if cfg.DigestPath == "" {
+			newValue = withoutDigest(newValue) + "@" + digest
+		}
+	}
+
+	// Replace the scalar the path resolves to, keeping its quotes; values that are not
+	// simple scalars fall back to a replacement keyed by the last key of the path
+	update := &yamlUpdate{}
+	edit := scalarEdit{path: yamlPath, value: quoteRetyped(cfg, node, newValue, warn)}
+	if replaced, replacement, ok := embeddedReplacement(cfg, valueAtPath, newValue, currentVersion, newVersion); ok {
+		edit.replaced, edit.replacement = replaced, replacement
+	}
+	if span, err := scalarText(data, node, edit); err != nil {
+		update.fallbackErr = fmt.Errorf("value at path %s %w", yamlPath, err)
+		update.key, update.oldValue, update.fallbackValue = extractKeyFromPath(cfg.Path), valueAtPath, edit.value
+	} else {
+		update.edits = append(update.edits, span)
+	}
+
+	if cfg.DigestPath != "" {
+		span, err := digestEdit(data, file, cfg.DigestPath, digest)
+		if err != nil {
+			return nil, fmt.Errorf("writing digest to %s: %w", cfg.DigestPath, err)
+		}
+		update.edits = append(update.edits, span)
+	}
+	return update, nil
+}
+
+// digestEdit returns the edit setting the scalar at the dot-notation path to digest.
+func digestEdit(data []byte, file *ast.File, path, digest string) (textEdit, error) {
+	yamlPath, err := convertToYAMLPath(path)
+	if err != nil {
+		return textEdit{}, fmt.Errorf("invalid digest path %s: %w", path, err)
+	}
+	node, err := fileScalarNode(file, yamlPath)
+	if err != nil {
+		return textEdit{}, fmt.Errorf("path %s not found: %w", yamlPath, err)
+	}
+	span, err := scalarText(data, node, scalarEdit{path: yamlPath, value: digest})
+	if err != nil {
+		return textEdit{}, fmt.Errorf("value at path %s %w", yamlPath, err)
+	}
+	return span, nil
+}
+
+// replacementValue returns the value to write in place of valueAtPath: the value with its
+// embedded current version replaced, or the prefixed new version if it embeds none.
+// A different embedded version is reported as a mismatch.
+func replacementValue(cfg VersionFileConfig, valueAtPath, currentVersion, newVersion string) (string, error) {
+	// Build the old and new version strings with prefix or template
+	oldVersionStr, err := cfg.formatVersion(currentVersion)
+	if err != nil {
+		return "", err
+	}
+	newVersionStr, err := cfg.formatVersion(newVersion)
+	if err != nil {
+		return "", err
+	}
+
+	if cfg.Template != "" {
+		return templateReplacement(cfg, valueAtPath, oldVersionStr, newVersionStr)
+	}
 
-	// Determine what to replace: either the embedded version or the entire value
-	var oldValue, newValue string
 	if strings.Contains(valueAtPath, oldVersionStr) {
 		// Embedded version found - replace just the version portion
-		oldValue = valueAtPath
-		newValue = strings.Replace(valueAtPath, oldVersionStr, newVersionStr, 1)
-	} else if embeddedVersion := findEmbeddedVersion(valueAtPath, cfg.Prefix); embeddedVersion != "" {
+		return strings.Replace(valueAtPath, oldVersionStr, newVersionStr, 1), nil
+	}
+	if embeddedVersion := findEmbeddedVersion(valueAtPath, cfg.Prefix); embeddedVersion != "" {
 		// Value contains an embedded version, but it doesn't match currentVersion
 		// This indicates a version mismatch that should be fixed before releasing
-		return fmt.Errorf("version mismatch in %s at path %s: "+
+		return "", fmt.Errorf("version mismatch in %s at path %s: "+
 			"expected to find %q but found %q in value %q. "+
 			"This usually means the file was not updated in a previous release. "+
 			"Please manually update the version in this file to %q before running releaseo",
 			cfg.File, cfg.Path, oldVersionStr, embeddedVersion, valueAtPath, oldVersionStr)
-	} else {
-		// No embedded version - replace the entire value (original behavior)
-		oldValue = valueAtPath
-		newValue = newVersionStr
 	}
+	// No embedded version - replace the entire value (original behavior)
+	return newVersionStr, nil
+}
 
-	// Extract the key name from the path for targeted replacement
-	key := extractKeyFromPath(cfg.Path)
+// templateReplacement replaces the current version rendered with cfg.Template,
+// oldVersionStr, in value. Any value the template could have produced is a version, so
+// versions are found with the template's pattern, skipping numbers within a longer dotted
+// sequence as marked regions do: a bare {{ .Major }} then matches 10 as a whole, which is
+// a stale version rather than a 1 to bump. A value holding no version is replaced entirely.
+func templateReplacement(cfg VersionFileConfig, value, oldVersionStr, newVersionStr string) (string, error) {
+	pattern, err := cfg.versionPattern()
+	if err != nil {
+		return "", err
+	}
+	for _, m := range pattern.FindAllStringIndex(value, -1) {
+		start, end := m[0], m[1]
+		if inDottedNumber([]byte(value), start, end) {
+			continue
+		}
+		if found := value[start:end]; found != oldVersionStr {
+			return "", fmt.Errorf("version mismatch in %s at path %s: "+
+				"expected to find %q but found %q in value %q. "+
+				"This usually means the file was not updated in a previous release. "+
+				"Please manually update the version in this file to %q before running releaseo",
+				cfg.File, cfg.Path, oldVersionStr, found, value, oldVersionStr)
+		}
+		return value[:start] + newVersionStr + value[end:], nil
+	}
+	return newVersionStr, nil
+}
 
-	// Perform surgical replacement - find and replace only the value for this specific key
-	newData, err := surgicalReplace(data, key, oldValue, newValue)
+// embeddedReplacement reports whether newValue replaces the formatted current version
+// embedded in value with the new one, returning both, as block scalars are edited.
+func embeddedReplacement(cfg VersionFileConfig, value, newValue, currentVersion, newVersion string) (string, string, bool) {
+	replaced, err := cfg.formatVersion(currentVersion)
 	if err != nil {
-		return fmt.Errorf("replacing value at path %s: %w", cfg.Path, err)
+		return "", "", false
+	}
+	replacement, err := cfg.formatVersion(newVersion)
+	if err != nil || !strings.Contains(value, replaced) {
+		return "", "", false
 	}
+	return replaced, replacement, strings.Replace(value, replaced, replacement, 1) == newValue
+}
 
-	// Write the file back
-	if err := os.WriteFile(cfg.File, newData, 0644); err != nil {
-		return fmt.Errorf("writing file %s: %w", cfg.File, err)
+// imageReference returns the reference to resolve for a value: the value itself if it
+// is a full image reference, or repository:value if a repository is given for a tag.
+func imageReference(repository, value string) string {
+	if repository != "" {
+		return repository + ":" + withoutDigest(value)
 	}
+	return withoutDigest(value)
+}
 
-	return nil
+// withoutDigest strips a digest (@sha256:...) from an image reference.
+func withoutDigest(ref string) string {
+	name, _, _ := strings.Cut(ref, "@")
+	return name
+}
+
+// resolveDigest returns the digest of the image reference.
+func resolveDigest(digests DigestResolver, ref string) (string, error) {
+	if digests == nil {
+		return "", fmt.Errorf("pinning %s by digest requires a registry client", ref)
+	}
+	digest, err := digests.Digest(context.Background(), ref)
+	if err != nil {
+		return "", fmt.Errorf("resolving digest of %s (is the image pushed?): %w", ref, err)
+	}
+	return digest, nil
+}
+
+// bumpOwnVersion returns the new value for an entry with its own version stream: the
+// version held in value, after the prefix, bumped by cfg.Bump.
+func bumpOwnVersion(cfg VersionFileConfig, value string) (string, error) {
+	current, err := version.Parse(strings.TrimPrefix(value, cfg.Prefix))
+	if err != nil {
+		return "", fmt.Errorf("value %q in %s at path %s is not a %sMAJOR.MINOR.PATCH version: %w",
+			value, cfg.File, cfg.Path, cfg.Prefix, err)
+	}
+	next, err := current.Bump(cfg.Bump)
+	if err != nil {
+		return "", err
+	}
+	return cfg.Prefix + next.String(), nil
 }
 
 // extractKeyFromPath extracts the final key name from a dot-notation path.
@@ -106,17 +576,13 @@ func UpdateYAMLFile(cfg VersionFileConfig, currentVersion, newVersion string) er
 //	"metadata.version" -> "version"
 //	"spec.containers[0].image" -> "image"
 //	"operator.image" -> "image"
+//	`metadata.annotations["app.kubernetes.io/version"]` -> "app.kubernetes.io/version"
 func extractKeyFromPath(path string) string {
-	// Remove any array indices from the path
-	re := regexp.MustCompile(`\[\d+\]`)
-	cleanPath := re.ReplaceAllString(path, "")
-
-	// Get the last component after splitting by dots
-	parts := strings.Split(cleanPath, ".")
-	if len(parts) == 0 {
+	segments, err := parsePath(path)
+	if err != nil {
 		return path
 	}
-	return parts[len(parts)-1]
+	return lastKey(segments)
 }
 
 // replacementRule defines a pattern-replacement pair for surgical YAML value replacement.
@@ -140,7 +606,7 @@ var replacementRules = []replacementRule{
 		// Handles double-quoted values: key: "value"
 		name: "double-quoted",
 		pattern: func(key, oldValue string) string {
-			return fmt.Sprintf(`(%s:\s*)"(%s)"`, regexp.QuoteMeta(key), regexp.QuoteMeta(oldValue))
+			return fmt.Sprintf(`(%s:\s*)"(%s)"`, keyPattern(key), regexp.QuoteMeta(oldValue))
 		},
 		replacement: func(_, newValue string) string {
 			// Use ${1} syntax to avoid ambiguity when newValue starts with a digit
@@ -151,7 +617,7 @@ var replacementRules = []replacementRule{
 		// Handles single-quoted values: key: 'value'
 		name: "single-quoted",
 		pattern: func(key, oldValue string) string {
-			return fmt.Sprintf(`(%s:\s*)'(%s)'`, regexp.QuoteMeta(key), regexp.QuoteMeta(oldValue))
+			return fmt.Sprintf(`(%s:\s*)'(%s)'`, keyPattern(key), regexp.QuoteMeta(oldValue))
 		},
 		replacement: func(_, newValue string) string {
 			return fmt.Sprintf(`${1}'%s'`, newValue)
@@ -161,7 +627,7 @@ var replacementRules = []replacementRule{
 		// Handles unquoted values at end of line: key: value\n
 		name: "unquoted-eol",
 		pattern: func(key, oldValue string) string {
-			return fmt.Sprintf(`(%s:\s*)(%s)(\s*)$`, regexp.QuoteMeta(key), regexp.QuoteMeta(oldValue))
+			return fmt.Sprintf(`(%s:\s*)(%s)(\s*)$`, keyPattern(key), regexp.QuoteMeta(oldValue))
 		},
 		replacement: func(_, newValue string) string {
 			return fmt.Sprintf(`${1}%s${3}`, newValue)
@@ -171,7 +637,7 @@ var replacementRules = []replacementRule{
 		// Handles unquoted values followed by inline comment: key: value # comment
 		name: "unquoted-with-comment",
 		pattern: func(key, oldValue string) string {
-			return fmt.Sprintf(`(%s:\s*)(%s)(\s*#)`, regexp.QuoteMeta(key), regexp.QuoteMeta(oldValue))
+			return fmt.Sprintf(`(%s:\s*)(%s)(\s*#)`, keyPattern(key), regexp.QuoteMeta(oldValue))
 		},
 		replacement: func(_, newValue string) string {
 			return fmt.Sprintf(`${1}%s${3}`, newValue)
@@ -197,15 +663,295 @@ func surgicalReplace(data []byte, key, oldValue, newValue string) ([]byte, error
 
 	// Fallback: key-aware simple string replacement if no pattern matched
 	// Look for "key: oldValue" or "key:oldValue" patterns
-	keyPattern := regexp.MustCompile(fmt.Sprintf(`(%s:\s*)%s`, regexp.QuoteMeta(key), regexp.QuoteMeta(oldValue)))
-	if keyPattern.MatchString(content) {
-		result := keyPattern.ReplaceAllString(content, fmt.Sprintf(`${1}%s`, newValue))
+	fallback := regexp.MustCompile(fmt.Sprintf(`(%s:\s*)%s`, keyPattern(key), regexp.QuoteMeta(oldValue)))
+	if fallback.MatchString(content) {
+		result := fallback.ReplaceAllString(content, fmt.Sprintf(`${1}%s`, newValue))
 		return []byte(result), nil
 	}
 
 	return nil, fmt.Errorf("could not find value %q for key %q to replace", oldValue, key)
 }
 
+// scalarEdit sets the scalar at a YAML path to a new value.
+type scalarEdit struct {
+	path  string
+	value string
+	// replaced and replacement, if set, give the change to the value as a replacement
+	// within it. Block scalars are edited this way, which keeps their lines as they are.
+	replaced, replacement string
+}
+
+// replaceScalars applies the edits to data. Each scalar is located by its position in the
+// parsed document, so only that occurrence changes even when other keys share its name or
+// value. Quotes, comments and all other formatting are preserved. Scalars can be in flow
+// mappings and sequences; a path through an alias edits the anchored scalar, and with it
+// every alias of the anchor. Block scalars (| and >) need edit.replaced.
+func replaceScalars(data []byte, edits []scalarEdit) ([]byte, error) {
+	file, err := parser.ParseBytes(data, 0)
+	if err != nil {
+		return nil, fmt.Errorf("parsing YAML: %w", err)
+	}
+
+	spans := make([]textEdit, 0, len(edits))
+	for _, edit := range edits {
+		node, err := fileScalarNode(file, edit.path)
+		if err != nil {
+			return nil, fmt.Errorf("path %s not found: %w", edit.path, err)
+		}
+
+		span, err := scalarText(data, node, edit)
+		if err != nil {
+			return nil, fmt.Errorf("value at path %s %w", edit.path, err)
+		}
+		spans = append(spans, span)
+	}
+	return applyEdits(data, spans), nil
+}
+
+// readScalar returns the value of the scalar at a YAML path, through aliases.
+func readScalar(data []byte, yamlPath string) (string, error) {
+	_, value, err := rawScalar(data, yamlPath)
+	return value, err
+}
+
+// scalarNode returns the scalar node at a YAML path, through aliases.
+func scalarNode(data []byte, yamlPath string) (ast.Node, error) {
+	file, err := parser.ParseBytes(data, 0)
+	if err != nil {
+		return nil, fmt.Errorf("parsing YAML: %w", err)
+	}
+	return fileScalarNode(file, yamlPath)
+}
+
+// fileScalarNode returns the scalar node at a YAML path in a parsed file, through aliases.
+func fileScalarNode(file *ast.File, yamlPath string) (ast.Node, error) {
+	path, err := yaml.PathString(yamlPath)
+	if err != nil {
+		return nil, fmt.Errorf("creating path %s: %w", yamlPath, err)
+	}
+	node, err := path.FilterFile(file)
+	if err != nil {
+		return nil, err
+	}
+	return resolveScalar(file, node)
+}
+
+// rawScalar returns the scalar node at a YAML path in data, through aliases, and its
+// value as written.
+func rawScalar(data []byte, yamlPath string) (ast.Node, string, error) {
+	node, err := scalarNode(data, yamlPath)
+	if err != nil {
+		return nil, "", err
+	}
+	value, err := scalarValue(node)
+	if err != nil {
+		return nil, "", err
+	}
+	return node, value, nil
+}
+
+// scalarValue returns the value of a scalar node. Numbers, booleans and other scalars
+// that are not strings are returned as written, so that 1.10 is not read as 1.1.
+func scalarValue(node ast.Node) (string, error) {
+	switch node.Type() {
+	case ast.IntegerType, ast.FloatType, ast.BoolType, ast.InfinityType, ast.NanType:
+		return node.GetToken().Value, nil
+	}
+	var value string
+	if err := yaml.NodeToValue(node, &value); err != nil {
+		return "", err
+	}
+	return value, nil
+}
+
+// quoteRetyped returns the text to write for value in place of node. If the scalar is
+// plain and YAML would read value as another type than the current one, or lose its form
+// (1.10 read as the float 1.1), it is reported to warn, and double-quoted with cfg.Quote.
+func quoteRetyped(cfg VersionFileConfig, node ast.Node, value string, warn func(string)) string {
+	kind := retypedAs(node, value)
+	if kind == "" {
+		return value
+	}
+	if cfg.Quote {
+		if warn != nil {
+			warn(fmt.Sprintf("%s at path %s: quoted %s, which YAML would otherwise read as a %s", cfg.File, cfg.Path, value, kind))
+		}
+		return `"` + value + `"`
+	}
+	if warn != nil {
+		warn(fmt.Sprintf("%s at path %s: YAML reads %s as a %s, not a string; set quote: true to write it quoted",
+			cfg.File, cfg.Path, value, kind))
+	}
+	return value
+}
+
+// retypedAs returns the type YAML reads value as when it replaces the plain scalar node,
+// if that is not a string and either differs from the node's type or loses the form of
+// value. It returns "" otherwise, and for quoted and block scalars, which stay strings.
+func retypedAs(node ast.Node, value string) string {
+	if t := node.GetToken().Type; node.Type() == ast.LiteralType || t == yamltoken.SingleQuoteType || t == yamltoken.DoubleQuoteType {
+		return ""
+	}
+	file, err := parser.ParseBytes([]byte(value), 0)
+	if err != nil || len(file.Docs) != 1 || file.Docs[0].Body == nil {
+		return ""
+	}
+
+	typ := file.Docs[0].Body.Type()
+	if typ == ast.StringType || (typ == node.Type() && !lossyNumber(value)) {
+		return ""
+	}
+	switch typ {
+	case ast.IntegerType, ast.FloatType, ast.InfinityType, ast.NanType:
+		return "number"
+	case ast.BoolType:
+		return "boolean"
+	case ast.NullType:
+		return "null"
+	default:
+		return strings.ToLower(typ.String())
+	}
+}
+
+// lossyNumber reports whether value is a number that does not read back as written,
+// such as 1.10 or 2.0.
+func lossyNumber(value string) bool {
+	f, err := strconv.ParseFloat(value, 64)
+	return err == nil && strconv.FormatFloat(f, 'f', -1, 64) != value
+}
+
+// resolveScalar returns the scalar that node holds, skipping its anchor and tag; for an
+// alias, it is the scalar of the last anchor of that name before it.
+func resolveScalar(file *ast.File, node ast.Node) (ast.Node, error) {
+	for {
+		switch n := node.(type) {
+		case *ast.AnchorNode:
+			node = n.Value
+		case *ast.TagNode:
+			node = n.Value
+		case *ast.AliasNode:
+			name := n.Value.GetToken().Value
+			var anchor *ast.AnchorNode
+			for _, a := range ast.FilterFile(ast.AnchorType, file) {
+				a := a.(*ast.AnchorNode)
+				if a.Name.GetToken().Value == name && before(a, n) {
+					anchor = a
+				}
+			}
+			if anchor == nil {
+				return nil, fmt.Errorf("anchor &%s not found", name)
+			}
+			node = anchor
+		default:
+			return node, nil
+		}
+	}
+}
+
+// before reports whether node a comes before node b in the document.
+func before(a, b ast.Node) bool {
+	pa, pb := a.GetToken().Position, b.GetToken().Position
+	return pa.Line < pb.Line || (pa.Line == pb.Line && pa.Column < pb.Column)
+}
+
+// scalarText returns the edit of the text of a scalar node in data.
+func scalarText(data []byte, node ast.Node, edit scalarEdit) (textEdit, error) {
+	tok := node.GetToken()
+	if literal, ok := node.(*ast.LiteralNode); ok {
+		// The content of a block scalar starts on the line after its header
+		start := lineColumnOffset(data, tok.Position.Line+1, 1)
+		origin := literal.Value.GetToken().Origin
+		if start < 0 || !bytes.HasPrefix(data[start:], []byte(origin)) {
+			return textEdit{}, fmt.Errorf("is a block scalar that could not be located")
+		}
+		i := strings.Index(origin, edit.replaced)
+		if edit.replaced == "" || i < 0 {
+			return textEdit{}, fmt.Errorf("is a block scalar, in which only a version in the text can be replaced")
+		}
+		start += i
+		return textEdit{start: start, end: start + len(edit.replaced), text: edit.replacement}, nil
+	}
+
+	start := lineColumnOffset(data, tok.Position.Line, tok.Position.Column)
+	// The column of a tagged scalar points at the blank before it
+	for start >= 0 && start < len(data) && (data[start] == ' ' || data[start] == '\t') {
+		start++
+	}
+	if start < 0 || start >= len(data) {
+		return textEdit{}, fmt.Errorf("is not a simple scalar")
+	}
+	quote := ""
+	if data[start] == '"' || data[start] == '\'' {
+		quote = string(data[start])
+	}
+	raw := quote + tok.Value + quote
+	if !bytes.HasPrefix(data[start:], []byte(raw)) {
+		return textEdit{}, fmt.Errorf("is not a simple scalar")
+	}
+	return textEdit{start: start, end: start + len(raw), text: quote + edit.value + quote}, nil
+}
+
+// removeKey deletes the entry at yamlPath, whose key is key, from a block mapping. The
+// key and its scalar value must be on a line of their own; keys in flow mappings or
+// after a sequence dash cannot be removed without reformatting and are rejected.
+func removeKey(data []byte, yamlPath, key string) ([]byte, error) {
+	file, err := parser.ParseBytes(data, 0)
+	if err != nil {
+		return nil, fmt.Errorf("parsing YAML: %w", err)
+	}
+	path, err := yaml.PathString(yamlPath)
+	if err != nil {
+		return nil, fmt.Errorf("creating path %s: %w", yamlPath, err)
+	}
+	node, err := path.FilterFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("path %s not found: %w", yamlPath, err)
+	}
+
+	start := lineColumnOffset(data, node.GetToken().Position.Line, 1)
+	if start < 0 {
+		return nil, fmt.Errorf("path %s not found", yamlPath)
+	}
+	end := len(data)
+	if i := bytes.IndexByte(data[start:], '\n'); i >= 0 {
+		end = start + i + 1
+	}
+
+	line := regexp.MustCompile(`^[ \t]*` + keyPattern(key) + `:[ \t]*\S*[ \t]*(#.*)?\r?\n?$`)
+	if !line.Match(data[start:end]) {
+		return nil, fmt.Errorf("cannot remove %s: it must be on a line of its own in a block mapping", yamlPath)
+	}
+	return append(data[:start:start], data[end:]...), nil
+}
+
+// lineColumnOffset returns the byte offset of a 1-based line and column (in characters),
+// or -1 if it is outside data.
+func lineColumnOffset(data []byte, line, column int) int {
+	offset := 0
+	for l := 1; l < line; l++ {
+		i := bytes.IndexByte(data[offset:], '\n')
+		if i < 0 {
+			return -1
+		}
+		offset += i + 1
+	}
+	for c := 1; c < column; c++ {
+		if offset >= len(data) {
+			return -1
+		}
+		_, size := utf8.DecodeRune(data[offset:])
+		offset += size
+	}
+	return offset
+}
+
+// keyPattern returns a regex matching the given key either bare or quoted,
+// so that JSON documents (e.g., package.json) can be updated the same way as YAML.
+func keyPattern(key string) string {
+	quoted := regexp.QuoteMeta(key)
+	return fmt.Sprintf(`(?:"%s"|'%s'|%s)`, quoted, quoted, quoted)
+}
+
 // findEmbeddedVersion looks for a version pattern in the value and returns it if found.
 // It detects patterns like ":v1.2.3", ":1.2.3", or prefix followed by semver at end of string.
 // Returns empty string if no embedded version is detected.
@@ -239,6 +985,7 @@ func findEmbeddedVersion(value, prefix string) string {
 //	"metadata.version" -> "$.metadata.version"
 //	"containers[0].image" -> "$.containers[0].image"
 //	"spec.template.spec.image.tag" -> "$.spec.template.spec.image.tag"
+//	`labels["helm.sh/chart"]` -> "$.labels.'helm.sh/chart'"
 func convertToYAMLPath(path string) (string, error) {
 	// Validate path is not empty
 	if path == "" {
@@ -255,5 +1002,9 @@ func convertToYAMLPath(path string) (string, error) {
 	if strings.HasPrefix(path, "$") {
 		return path, nil
 	}
-	return "$." + path, nil
+	segments, err := parsePath(path)
+	if err != nil {
+		return "", err
+	}
+	return yamlPathString(segments), nil
 }
@@ -104,6 +104,19 @@ name: myapp
 			newVersion:     "2.0.0",
 			wantContain:    "version: 2.0.0",
 		},
+		{
+			name: "json document with quoted keys",
+			input: `{
+  "name": "app",
+  "version": "1.0.0",
+  "private": true
+}
+`,
+			config:         VersionFileConfig{Path: "version"},
+			currentVersion: "1.0.0",
+			newVersion:     "2.0.0",
+			wantContain:    `"version": "2.0.0",`,
+		},
 		{
 			name: "key not found",
 			input: `metadata:
@@ -124,6 +137,30 @@ name: myapp
 			newVersion:     "2.0.0",
 			wantErr:        true,
 		},
+		{
+			name:           "own version stream",
+			input:          "name: chart\nversion: 0.4.2\nappVersion: 1.0.0\n",
+			config:         VersionFileConfig{Path: "version", Bump: "minor"},
+			currentVersion: "1.0.0",
+			newVersion:     "1.0.1",
+			wantContain:    "version: 0.5.0\nappVersion: 1.0.0",
+		},
+		{
+			name:           "own version stream with prefix",
+			input:          "image:\n  tag: \"v3.9.9\"\n",
+			config:         VersionFileConfig{Path: "image.tag", Prefix: "v", Bump: "major"},
+			currentVersion: "1.0.0",
+			newVersion:     "1.0.1",
+			wantContain:    `tag: "v4.0.0"`,
+		},
+		{
+			name:           "own version stream without a version",
+			input:          "image:\n  tag: latest\n",
+			config:         VersionFileConfig{Path: "image.tag", Bump: "patch"},
+			currentVersion: "1.0.0",
+			newVersion:     "1.0.1",
+			wantErr:        true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -229,6 +266,12 @@ func TestConvertToYAMLPath(t *testing.T) {
 		{"spec.template.spec.image.tag", "$.spec.template.spec.image.tag", false},
 		{"containers[0].image", "$.containers[0].image", false},
 		{"$.already.prefixed", "$.already.prefixed", false},
+		{`metadata.annotations["app.kubernetes.io/version"]`, "$.metadata.annotations.'app.kubernetes.io/version'", false},
+		{`labels.'helm.sh/chart'`, "$.labels.'helm.sh/chart'", false},
+		{`data["it's"][0]`, `$.data.'it\'s'[0]`, false},
+		{`labels["unterminated]`, "", true},
+		{"items[x]", "", true},
+		{"trailing.", "", true},
 		// Error cases
 		{".image.tag", "", true},  // Leading dot
 		{".version", "", true},    // Leading dot
@@ -266,6 +309,8 @@ func TestExtractKeyFromPath(t *testing.T) {
 		{"containers[0].image", "image"},
 		{"spec.containers[0].image", "image"},
 		{"data[0]", "data"},
+		{`metadata.annotations["app.kubernetes.io/version"]`, "app.kubernetes.io/version"},
+		{`labels."helm.sh/chart"`, "helm.sh/chart"},
 	}
 
 	for _, tt := range tests {
@@ -747,3 +792,487 @@ appVersion: "0.9.0"
 		})
 	}
 }
+
+func TestVersionFileConfig_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		cfg     VersionFileConfig
+		wantErr string
+	}{
+		{name: "yaml", cfg: VersionFileConfig{File: "values.yaml", Path: "image.tag"}},
+		{name: "explicit yaml", cfg: VersionFileConfig{File: "values.yaml", Path: "image.tag", Type: TypeYAML}},
+		{name: "helm", cfg: VersionFileConfig{File: "charts/app", Type: TypeHelm, Prefix: "v"}},
+		{name: "no file", cfg: VersionFileConfig{Path: "version"}, wantErr: "file is required"},
+		{name: "yaml without path", cfg: VersionFileConfig{File: "values.yaml"}, wantErr: "path is required"},
+		{name: "helm with path", cfg: VersionFileConfig{File: "charts/app", Type: TypeHelm, Path: "version"}, wantErr: "path cannot be used"},
+		{name: "unknown type", cfg: VersionFileConfig{File: "Cargo.toml", Type: "toml"}, wantErr: "unknown type"},
+		{name: "kustomize", cfg: VersionFileConfig{File: "kustomization.yaml", Type: TypeKustomize, Image: "app", ClearDigest: true}},
+		{name: "kustomize without image", cfg: VersionFileConfig{File: "kustomization.yaml", Type: TypeKustomize}, wantErr: "image is required"},
+		{name: "gomod", cfg: VersionFileConfig{File: "go.mod", Type: TypeGoMod}},
+		{name: "gomod with bump", cfg: VersionFileConfig{File: "go.mod", Type: TypeGoMod, Bump: "major"}, wantErr: "cannot be used with type gomod"},
+		{name: "pin gomod", cfg: VersionFileConfig{File: "go.mod", Type: TypeGoMod, PinDigest: true}, wantErr: "cannot be used with type gomod"},
+		{name: "go", cfg: VersionFileConfig{File: "version.go", Type: TypeGo, Path: "Version"}},
+		{name: "go without identifier", cfg: VersionFileConfig{File: "version.go", Type: TypeGo, Path: "build.Version"}, wantErr: "must name a constant"},
+		{name: "xml without path", cfg: VersionFileConfig{File: "pom.xml", Type: TypeXML}, wantErr: "path is required"},
+		{name: "keyvalue", cfg: VersionFileConfig{File: "gradle.properties", Type: TypeKeyValue, Path: "version"}},
+		{name: "marker", cfg: VersionFileConfig{File: "docs/**/*.md", Type: TypeMarker}},
+		{name: "marker with path", cfg: VersionFileConfig{File: "README.md", Type: TypeMarker, Path: "x"}, wantErr: "cannot be used with type marker"},
+		{name: "template", cfg: VersionFileConfig{File: "values.yaml", Path: "docs.channel", Template: "{{ .Major }}.{{ .Minor }}"}},
+		{name: "template and prefix", cfg: VersionFileConfig{File: "values.yaml", Path: "tag", Template: "{{ .Full }}", Prefix: "v"}, wantErr: "mutually exclusive"},
+		{name: "template and bump", cfg: VersionFileConfig{File: "values.yaml", Path: "tag", Template: "{{ .Full }}", Bump: "patch"}, wantErr: "bump cannot be used with template"},
+		{name: "helm template and bump", cfg: VersionFileConfig{File: "charts/app", Type: TypeHelm, Template: "v{{ .Full }}", Bump: "patch"}},
+		{name: "invalid template", cfg: VersionFileConfig{File: "values.yaml", Path: "tag", Template: "{{ .Version }}"}, wantErr: "evaluating template"},
+		{name: "image without kustomize", cfg: VersionFileConfig{File: "values.yaml", Path: "tag", Image: "app"}, wantErr: "only be used with type kustomize"},
+		{name: "pin inline", cfg: VersionFileConfig{File: "values.yaml", Path: "image", PinDigest: true}},
+		{
+			name: "pin to digest path",
+			cfg:  VersionFileConfig{File: "values.yaml", Path: "image.tag", Image: "ghcr.io/acme/app", PinDigest: true, DigestPath: "image.digest"},
+		},
+		{name: "digest path without pin", cfg: VersionFileConfig{File: "values.yaml", Path: "tag", DigestPath: "digest"}, wantErr: "requires pin_digest"},
+		{name: "pin helm chart", cfg: VersionFileConfig{File: "charts/app", Type: TypeHelm, PinDigest: true}, wantErr: "cannot be used with type helm"},
+		{
+			name:    "pin and clear",
+			cfg:     VersionFileConfig{File: "kustomization.yaml", Type: TypeKustomize, Image: "app", PinDigest: true, ClearDigest: true},
+			wantErr: "mutually exclusive",
+		},
+		{name: "quote helm", cfg: VersionFileConfig{File: "charts/app", Type: TypeHelm, Quote: true}},
+		{name: "quote marker", cfg: VersionFileConfig{File: "README.md", Type: TypeMarker, Quote: true}, wantErr: "quote cannot be used with type marker"},
+		{name: "own bump", cfg: VersionFileConfig{File: "charts/app", Type: TypeHelm, Bump: "minor"}},
+		{name: "same bump", cfg: VersionFileConfig{File: "values.yaml", Path: "chart.version", Bump: BumpSame}},
+		{name: "invalid bump", cfg: VersionFileConfig{File: "values.yaml", Path: "v", Bump: "huge"}, wantErr: "invalid bump"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := tt.cfg.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestReplaceScalars(t *testing.T) {
+	t.Parallel()
+
+	input := `version: 1.0.0 # chart
+image:
+  tag: "1.0.0"
+sidecar: {tag: '1.0.0', version: 1.0.0}
+items:
+  - version: 1.0.0
+`
+	got, err := replaceScalars([]byte(input), []scalarEdit{
+		{path: "$.items[0].version", value: "2.0.0"},
+		{path: "$.image.tag", value: "2.0.0"},
+		{path: "$.sidecar.tag", value: "2.0.0"},
+	})
+	if err != nil {
+		t.Fatalf("replaceScalars() error = %v", err)
+	}
+
+	want := `version: 1.0.0 # chart
+image:
+  tag: "2.0.0"
+sidecar: {tag: '2.0.0', version: 1.0.0}
+items:
+  - version: 2.0.0
+`
+	if string(got) != want {
+		t.Errorf("replaceScalars() =\n%s\nwant\n%s", got, want)
+	}
+
+	if _, err := replaceScalars([]byte(input), []scalarEdit{{path: "$.missing", value: "x"}}); err == nil {
+		t.Error("replaceScalars() with a missing path: expected error")
+	}
+}
+
+func TestUpdateYAMLFile_PinDigest(t *testing.T) {
+	t.Parallel()
+
+	const (
+		oldDigest = "sha256:1111111111111111111111111111111111111111111111111111111111111111"
+		newDigest = "sha256:2222222222222222222222222222222222222222222222222222222222222222"
+	)
+	digests := fakeDigests{
+		"ghcr.io/acme/app:v1.1.0": newDigest,
+	}
+
+	tests := []struct {
+		name    string
+		input   string
+		cfg     VersionFileConfig
+		want    string
+		wantErr string
+	}{
+		{
+			name:  "inline digest",
+			input: "image: ghcr.io/acme/app:v1.0.0\n",
+			cfg:   VersionFileConfig{Path: "image", Prefix: "v", PinDigest: true},
+			want:  "image: ghcr.io/acme/app:v1.1.0@" + newDigest + "\n",
+		},
+		{
+			name:  "inline digest replacing a previous one",
+			input: "image: \"ghcr.io/acme/app:v1.0.0@" + oldDigest + "\"\n",
+			cfg:   VersionFileConfig{Path: "image", Prefix: "v", PinDigest: true},
+			want:  "image: \"ghcr.io/acme/app:v1.1.0@" + newDigest + "\"\n",
+		},
+		{
+			name:  "separate digest key",
+			input: "image:\n  repository: ghcr.io/acme/app\n  tag: v1.0.0\n  digest: \"" + oldDigest + "\"\n",
+			cfg: VersionFileConfig{
+				Path: "image.tag", Prefix: "v", PinDigest: true, Image: "ghcr.io/acme/app", DigestPath: "image.digest",
+			},
+			want: "image:\n  repository: ghcr.io/acme/app\n  tag: v1.1.0\n  digest: \"" + newDigest + "\"\n",
+		},
+		{
+			name:    "image not pushed",
+			input:   "image: ghcr.io/acme/other:v1.0.0\n",
+			cfg:     VersionFileConfig{Path: "image", Prefix: "v", PinDigest: true},
+			wantErr: "image not found",
+		},
+		{
+			name:    "missing digest key",
+			input:   "image:\n  tag: v1.0.0\n",
+			cfg:     VersionFileConfig{Path: "image.tag", Prefix: "v", PinDigest: true, Image: "ghcr.io/acme/app", DigestPath: "image.digest"},
+			wantErr: "writing digest",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := tt.cfg
+			cfg.File = createTempFile(t, tt.input, "values-*.yaml")
+			updater := &DefaultYAMLUpdater{Digests: digests}
+
+			err := updater.UpdateYAMLFile(cfg, "1.0.0", "1.1.0")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("UpdateYAMLFile() error = %v, want to contain %q", err, tt.wantErr)
+				}
+				if got := readTempFile(t, cfg.File); got != tt.input {
+					t.Errorf("file modified on error:\n%s", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("UpdateYAMLFile() error = %v", err)
+			}
+			if got := readTempFile(t, cfg.File); got != tt.want {
+				t.Errorf("UpdateYAMLFile() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+
+	// Without a resolver, pinning is refused rather than silently skipped
+	cfg := VersionFileConfig{File: createTempFile(t, "image: ghcr.io/acme/app:v1.0.0\n", "values-*.yaml"), Path: "image", PinDigest: true}
+	if err := UpdateYAMLFile(cfg, "1.0.0", "1.1.0"); err == nil || !strings.Contains(err.Error(), "requires a registry client") {
+		t.Errorf("UpdateYAMLFile() error = %v, want registry client error", err)
+	}
+}
+
+func TestUpdateYAMLFile_Structures(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		input   string
+		path    string
+		want    string
+		wantErr string
+	}{
+		{
+			name:  "flow mapping",
+			input: "image: {repository: ghcr.io/acme/app, tag: 1.2.3}\nsidecar: {tag: 1.2.3}\n",
+			path:  "image.tag",
+			want:  "image: {repository: ghcr.io/acme/app, tag: 1.3.0}\nsidecar: {tag: 1.2.3}\n",
+		},
+		{
+			name:  "flow sequence",
+			input: "tags: [latest, \"1.2.3\", 1.2.3]\n",
+			path:  "tags[2]",
+			want:  "tags: [latest, \"1.2.3\", 1.3.0]\n",
+		},
+		{
+			name:  "alias updates the anchor",
+			input: "defaults:\n  version: &version 1.2.3\napp:\n  tag: *version\nworker:\n  tag: *version\n",
+			path:  "app.tag",
+			want:  "defaults:\n  version: &version 1.3.0\napp:\n  tag: *version\nworker:\n  tag: *version\n",
+		},
+		{
+			name:  "anchor definition",
+			input: "version: &v \"1.2.3\" # shared\nimage:\n  tag: *v\n",
+			path:  "version",
+			want:  "version: &v \"1.3.0\" # shared\nimage:\n  tag: *v\n",
+		},
+		{
+			name:  "redefined anchor",
+			input: "a: &v 1.2.3\nb: *v\nc: &v 1.2.3\nd: *v\n",
+			path:  "d",
+			want:  "a: &v 1.2.3\nb: *v\nc: &v 1.3.0\nd: *v\n",
+		},
+		{
+			name:  "tagged scalar",
+			input: "version: !!str 1.2.3\n",
+			path:  "version",
+			want:  "version: !!str 1.3.0\n",
+		},
+		{
+			name:  "literal block scalar",
+			input: "notes: |\n  Install with:\n    helm install app --version 1.2.3\n  Then enjoy 1.2.3.\nnext: 1.2.3\n",
+			path:  "notes",
+			want:  "notes: |\n  Install with:\n    helm install app --version 1.3.0\n  Then enjoy 1.2.3.\nnext: 1.2.3\n",
+		},
+		{
+			name:  "folded block scalar",
+			input: "description: >- # summary\n  Release\n  1.2.3 of the app\n",
+			path:  "description",
+			want:  "description: >- # summary\n  Release\n  1.3.0 of the app\n",
+		},
+		{
+			name:    "block scalar without version",
+			input:   "notes: |\n  Install the latest release\n",
+			path:    "notes",
+			wantErr: "only a version in the text can be replaced",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := VersionFileConfig{File: createTempFile(t, tt.input, "values-*.yaml"), Path: tt.path}
+			err := UpdateYAMLFile(cfg, "1.2.3", "1.3.0")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("UpdateYAMLFile() error = %v, want to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("UpdateYAMLFile() error = %v", err)
+			}
+			if got := readTempFile(t, cfg.File); got != tt.want {
+				t.Errorf("UpdateYAMLFile() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpdateYAMLFile_NonStringScalars(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		input       string
+		template    string
+		quote       bool
+		current     string
+		newVersion  string
+		want        string
+		wantWarning string
+	}{
+		{
+			name:       "float version",
+			input:      "version: 1.2 # minor\n",
+			template:   "{{.Major}}.{{.Minor}}",
+			current:    "1.2.3",
+			newVersion: "1.3.0",
+			want:       "version: 1.3 # minor\n",
+		},
+		{
+			name:       "integer version",
+			input:      "appVersion: 10\n",
+			template:   "{{.Major}}",
+			current:    "10.0.0",
+			newVersion: "11.0.0",
+			want:       "appVersion: 11\n",
+		},
+		{
+			name:        "float losing its form",
+			input:       "version: 1.9\n",
+			template:    "{{.Major}}.{{.Minor}}",
+			current:     "1.9.0",
+			newVersion:  "1.10.0",
+			want:        "version: 1.10\n",
+			wantWarning: "YAML reads 1.10 as a number, not a string; set quote: true",
+		},
+		{
+			name:        "float losing its form quoted",
+			input:       "version: 1.9\n",
+			template:    "{{.Major}}.{{.Minor}}",
+			quote:       true,
+			current:     "1.9.0",
+			newVersion:  "1.10.0",
+			want:        "version: \"1.10\"\n",
+			wantWarning: "quoted 1.10, which YAML would otherwise read as a number",
+		},
+		{
+			name:       "quoted float",
+			input:      "version: \"1.9\"\n",
+			template:   "{{.Major}}.{{.Minor}}",
+			quote:      true,
+			current:    "1.9.0",
+			newVersion: "1.10.0",
+			want:       "version: \"1.10\"\n",
+		},
+		{
+			name:       "semantic version",
+			input:      "version: 1.2.3\n",
+			quote:      true,
+			current:    "1.2.3",
+			newVersion: "1.3.0",
+			want:       "version: 1.3.0\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var warnings []string
+			updater := &DefaultYAMLUpdater{Warn: func(msg string) { warnings = append(warnings, msg) }}
+			cfg := VersionFileConfig{
+				File:     createTempFile(t, tt.input, "values-*.yaml"),
+				Path:     "$." + strings.SplitN(tt.input, ":", 2)[0],
+				Template: tt.template,
+				Quote:    tt.quote,
+			}
+			if err := updater.UpdateYAMLFile(cfg, tt.current, tt.newVersion); err != nil {
+				t.Fatalf("UpdateYAMLFile() error = %v", err)
+			}
+			if got := readTempFile(t, cfg.File); got != tt.want {
+				t.Errorf("UpdateYAMLFile() =\n%s\nwant\n%s", got, tt.want)
+			}
+
+			switch {
+			case tt.wantWarning == "" && len(warnings) > 0:
+				t.Errorf("UpdateYAMLFile() warnings = %q, want none", warnings)
+			case tt.wantWarning != "" && (len(warnings) != 1 || !strings.Contains(warnings[0], tt.wantWarning)):
+				t.Errorf("UpdateYAMLFile() warnings = %q, want one containing %q", warnings, tt.wantWarning)
+			}
+		})
+	}
+}
+
+func TestUpdateYAMLFiles(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		input    string
+		entries  []VersionFileConfig
+		want     string
+		wantErrs []string // one per entry, "" when the entry succeeds
+	}{
+		{
+			name:  "several values in one file",
+			input: "version: 1.2.3\nappVersion: \"v1.2.3\"\nimage:\n  tag: v1.2.3 # app\n",
+			entries: []VersionFileConfig{
+				{Path: "version"},
+				{Path: "appVersion", Prefix: "v"},
+				{Path: "image.tag", Prefix: "v"},
+			},
+			want:     "version: 1.3.0\nappVersion: \"v1.3.0\"\nimage:\n  tag: v1.3.0 # app\n",
+			wantErrs: []string{"", "", ""},
+		},
+		{
+			name:  "aliases of one anchor",
+			input: "defaults:\n  tag: &tag 1.2.3\napp:\n  tag: *tag\nworker:\n  tag: *tag\n",
+			entries: []VersionFileConfig{
+				{Path: "app.tag"},
+				{Path: "worker.tag"},
+			},
+			want:     "defaults:\n  tag: &tag 1.3.0\napp:\n  tag: *tag\nworker:\n  tag: *tag\n",
+			wantErrs: []string{"", ""},
+		},
+		{
+			name:  "conflicting edits",
+			input: "defaults:\n  tag: &tag 1.2.3\napp:\n  tag: *tag\nworker:\n  tag: *tag\nversion: 1.2.3\n",
+			entries: []VersionFileConfig{
+				{Path: "app.tag"},
+				{Path: "worker.tag", Bump: "patch"},
+				{Path: "version"},
+			},
+			want:     "defaults:\n  tag: &tag 1.3.0\napp:\n  tag: *tag\nworker:\n  tag: *tag\nversion: 1.3.0\n",
+			wantErrs: []string{"", "conflicts with the edit for path app.tag", ""},
+		},
+		{
+			name:  "failing entry",
+			input: "version: 1.2.3\n",
+			entries: []VersionFileConfig{
+				{Path: "missing"},
+				{Path: "version"},
+			},
+			want:     "version: 1.3.0\n",
+			wantErrs: []string{"path missing not found", ""},
+		},
+		{
+			name:     "invalid YAML",
+			input:    "version: [1.2.3\n",
+			entries:  []VersionFileConfig{{Path: "version"}, {Path: "other"}},
+			want:     "version: [1.2.3\n",
+			wantErrs: []string{"parsing YAML", "parsing YAML"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			file := createTempFile(t, tt.input, "values-*.yaml")
+			for i := range tt.entries {
+				tt.entries[i].File = file
+			}
+
+			errs := (&DefaultYAMLUpdater{}).UpdateYAMLFiles(tt.entries, "1.2.3", "1.3.0")
+			if len(errs) != len(tt.entries) {
+				t.Fatalf("UpdateYAMLFiles() returned %d errors, want %d", len(errs), len(tt.entries))
+			}
+			for i, err := range errs {
+				if tt.wantErrs[i] == "" && err != nil {
+					t.Errorf("UpdateYAMLFiles() entry %d error = %v", i, err)
+				}
+				if tt.wantErrs[i] != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErrs[i])) {
+					t.Errorf("UpdateYAMLFiles() entry %d error = %v, want to contain %q", i, err, tt.wantErrs[i])
+				}
+			}
+			if got := readTempFile(t, file); got != tt.want {
+				t.Errorf("UpdateYAMLFiles() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVersionFileConfig_Summary(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		cfg  VersionFileConfig
+		want string
+	}{
+		{cfg: VersionFileConfig{File: "values.yaml", Path: "image.tag"}, want: "`values.yaml` (path: `image.tag`)"},
+		{cfg: VersionFileConfig{File: "charts/app", Type: TypeHelm}, want: "`charts/app` (helm chart)"},
+		{cfg: VersionFileConfig{File: "kustomization.yaml", Type: TypeKustomize, Image: "app"}, want: "`kustomization.yaml` (image: `app`)"},
+		{cfg: VersionFileConfig{File: "go.mod", Type: TypeGoMod}, want: "`go.mod` (Go module)"},
+		{cfg: VersionFileConfig{File: "version.go", Type: TypeGo, Path: "Version"}, want: "`version.go` (path: `Version`)"},
+		{cfg: VersionFileConfig{File: "docs/**/*.md", Type: TypeMarker}, want: "`docs/**/*.md` (marked versions)"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.cfg.Summary(); got != tt.want {
+			t.Errorf("Summary() = %q, want %q", got, tt.want)
+		}
+	}
+}
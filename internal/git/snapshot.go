@@ -0,0 +1,102 @@
+// Copyright 2025 Stacklok, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package git
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// Snapshot records the dirty paths of a working tree and their content at a point in time,
+// so that the changes made by a later step can be told apart from pre-existing ones.
+type Snapshot struct {
+	dir     string
+	changes []Change
+	// digests holds a digest of each changed path's content, keyed by path.
+	digests map[string]string
+}
+
+// TakeSnapshot records the current changes in the working tree of the repository in dir,
+// or in the current directory if dir is empty.
+func TakeSnapshot(dir string) (*Snapshot, error) {
+	changes, err := Status(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Snapshot{dir: dir, changes: changes, digests: make(map[string]string, len(changes))}
+	for _, c := range changes {
+		digest, err := s.digest(c)
+		if err != nil {
+			return nil, err
+		}
+		s.digests[c.Path] = digest
+	}
+	return s, nil
+}
+
+// ChangesSince returns the changes in s that were not already present, with the same
+// content, in before. A file that was dirty before and has been modified again is included;
+// a file whose dirty state is unchanged is not.
+func (s *Snapshot) ChangesSince(before *Snapshot) []Change {
+	previous := make(map[string]Change, len(before.changes))
+	for _, c := range before.changes {
+		previous[c.Path] = c
+	}
+
+	var changes []Change
+	for _, c := range s.changes {
+		prev, ok := previous[c.Path]
+		if ok && prev == c && before.digests[c.Path] == s.digests[c.Path] {
+			continue
+		}
+		changes = append(changes, c)
+	}
+	return changes
+}
+
+// digest returns a digest of the content of the changed path, or an empty string if it no longer exists.
+func (s *Snapshot) digest(c Change) (string, error) {
+	if c.Kind == Deleted {
+		return "", nil
+	}
+
+	path := filepath.Join(s.dir, c.Path)
+	info, err := os.Lstat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("reading %s: %w", c.Path, err)
+	}
+
+	var content []byte
+	if info.Mode()&fs.ModeSymlink != 0 {
+		target, err := os.Readlink(path)
+		if err != nil {
+			return "", fmt.Errorf("reading symlink %s: %w", c.Path, err)
+		}
+		content = []byte(target)
+	} else if content, err = os.ReadFile(path); err != nil {
+		return "", fmt.Errorf("reading %s: %w", c.Path, err)
+	}
+
+	// The mode is part of the digest so that a chmod counts as a change
+	return fmt.Sprintf("%o:%x", info.Mode(), sha256.Sum256(content)), nil
+}
@@ -0,0 +1,152 @@
+// Copyright 2025 Stacklok, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package git provides utilities for inspecting the local git working tree.
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// ChangeKind describes how a path differs from HEAD.
+type ChangeKind string
+
+// Change kinds reported by Status.
+const (
+	Modified ChangeKind = "modified"
+	Added    ChangeKind = "added"
+	Deleted  ChangeKind = "deleted"
+	Renamed  ChangeKind = "renamed"
+)
+
+// Change is a single path that differs from HEAD in the working tree or index.
+type Change struct {
+	Kind ChangeKind
+	Path string
+	// OrigPath is the path the file was renamed from, set for Renamed changes.
+	OrigPath string
+}
+
+// Status returns the changes in the working tree of the repository in dir,
+// or in the current directory if dir is empty.
+// Untracked directories are expanded into the files they contain.
+func Status(dir string) ([]Change, error) {
+	cmd := exec.Command("git", "status", "--porcelain=v2", "-z", "--untracked-files=all")
+	cmd.Dir = dir
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("running git status: %w", err)
+	}
+	return ParseStatus(output)
+}
+
+// ParseStatus parses the output of git status --porcelain=v2 -z.
+// Paths are NUL terminated in this format, so they are never quoted or escaped.
+// Ignored entries and headers are skipped.
+func ParseStatus(output []byte) ([]Change, error) {
+	records := bytes.Split(output, []byte{0})
+
+	var changes []Change
+	for i := 0; i < len(records); i++ {
+		record := string(records[i])
+		if record == "" {
+			continue
+		}
+
+		switch record[0] {
+		case '1':
+			// 1 <XY> <sub> <mH> <mI> <mW> <hH> <hI> <path>
+			fields := strings.SplitN(record, " ", 9)
+			if len(fields) != 9 || len(fields[1]) != 2 {
+				return nil, fmt.Errorf("malformed git status entry %q", record)
+			}
+			if change, ok := ordinaryChange(fields[1], fields[8]); ok {
+				changes = append(changes, change)
+			}
+		case '2':
+			// 2 <XY> <sub> <mH> <mI> <mW> <hH> <hI> <X><score> <path>, followed by <origPath>
+			fields := strings.SplitN(record, " ", 10)
+			if len(fields) != 10 || len(fields[1]) != 2 || i+1 >= len(records) {
+				return nil, fmt.Errorf("malformed git status rename entry %q", record)
+			}
+			i++
+			changes = append(changes, renameChange(fields[1], fields[9], string(records[i])))
+		case 'u':
+			// u <XY> <sub> <m1> <m2> <m3> <mW> <h1> <h2> <h3> <path>
+			fields := strings.SplitN(record, " ", 11)
+			if len(fields) != 11 {
+				return nil, fmt.Errorf("malformed git status unmerged entry %q", record)
+			}
+			changes = append(changes, Change{Kind: Modified, Path: fields[10]})
+		case '?':
+			changes = append(changes, Change{Kind: Added, Path: strings.TrimPrefix(record, "? ")})
+		case '!', '#':
+			// Ignored files and headers
+		default:
+			return nil, fmt.Errorf("unknown git status entry %q", record)
+		}
+	}
+	return changes, nil
+}
+
+// ordinaryChange classifies an ordinary entry from its index (X) and working tree (Y) status.
+// It returns false for a file added to the index and then deleted, which has no change relative to HEAD.
+func ordinaryChange(xy, path string) (Change, bool) {
+	index, worktree := xy[0], xy[1]
+	switch {
+	case worktree == 'D' && index == 'A':
+		return Change{}, false
+	case worktree == 'D', index == 'D':
+		return Change{Kind: Deleted, Path: path}, true
+	case index == 'A':
+		return Change{Kind: Added, Path: path}, true
+	default:
+		return Change{Kind: Modified, Path: path}, true
+	}
+}
+
+// renameChange classifies a staged rename or copy of origPath to path.
+func renameChange(xy, path, origPath string) Change {
+	switch {
+	case xy[1] == 'D':
+		// Renamed in the index, then the new path was deleted from the working tree
+		return Change{Kind: Deleted, Path: origPath}
+	case xy[0] == 'C':
+		// A copy leaves the original in place
+		return Change{Kind: Added, Path: path}
+	default:
+		return Change{Kind: Renamed, Path: path, OrigPath: origPath}
+	}
+}
+
+// Paths splits changes into the paths whose current content must be committed
+// and the paths that must be deleted. A renamed file is written at its new path
+// and deleted at its original path.
+func Paths(changes []Change) (written, deleted []string) {
+	for _, c := range changes {
+		switch c.Kind {
+		case Deleted:
+			deleted = append(deleted, c.Path)
+		case Renamed:
+			written = append(written, c.Path)
+			deleted = append(deleted, c.OrigPath)
+		case Modified, Added:
+			written = append(written, c.Path)
+		}
+	}
+	return written, deleted
+}
@@ -0,0 +1,261 @@
+// Copyright 2025 Stacklok, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const (
+	// ordinary is the part of a "1" entry between XY and the path.
+	ordinary = " N... 100644 100644 100644 0123456789abcdef0123456789abcdef01234567 0123456789abcdef0123456789abcdef01234567 "
+	// renamed is the part of a "2" entry between XY and the score.
+	renamed = " N... 100644 100644 100644 0123456789abcdef0123456789abcdef01234567 0123456789abcdef0123456789abcdef01234567 "
+)
+
+// testRepo is a temporary git repository.
+type testRepo struct {
+	t   *testing.T
+	dir string
+}
+
+// newTestRepo initialises an empty repository, skipping the test if git is unavailable.
+func newTestRepo(t *testing.T) *testRepo {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	r := &testRepo{t: t, dir: t.TempDir()}
+	r.git("init", "-q")
+	return r
+}
+
+func (r *testRepo) git(args ...string) {
+	r.t.Helper()
+	cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+	cmd.Dir = r.dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		r.t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+}
+
+func (r *testRepo) write(path, content string) {
+	r.t.Helper()
+	full := filepath.Join(r.dir, path)
+	if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+		r.t.Fatal(err)
+	}
+	if err := os.WriteFile(full, []byte(content), 0o644); err != nil {
+		r.t.Fatal(err)
+	}
+}
+
+func TestParseStatus(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		output  string
+		want    []Change
+		wantErr bool
+	}{
+		{
+			name:   "empty",
+			output: "",
+		},
+		{
+			name:   "worktree modification with space in index column",
+			output: "1 .M" + ordinary + "charts/app/README.md\x00",
+			want:   []Change{{Kind: Modified, Path: "charts/app/README.md"}},
+		},
+		{
+			name:   "path with spaces is not quoted",
+			output: "1 M." + ordinary + "docs/release notes.md\x00",
+			want:   []Change{{Kind: Modified, Path: "docs/release notes.md"}},
+		},
+		{
+			name:   "staged add and untracked file",
+			output: "1 A." + ordinary + "new.yaml\x00? charts/new/README.md\x00",
+			want: []Change{
+				{Kind: Added, Path: "new.yaml"},
+				{Kind: Added, Path: "charts/new/README.md"},
+			},
+		},
+		{
+			name:   "deletions",
+			output: "1 .D" + ordinary + "old.md\x001 D." + ordinary + "staged-old.md\x00",
+			want: []Change{
+				{Kind: Deleted, Path: "old.md"},
+				{Kind: Deleted, Path: "staged-old.md"},
+			},
+		},
+		{
+			name:   "added then deleted is no change",
+			output: "1 AD" + ordinary + "tmp.txt\x00",
+		},
+		{
+			name:   "rename",
+			output: "2 R." + renamed + "R100 docs/new name.md\x00docs/old name.md\x00",
+			want:   []Change{{Kind: Renamed, Path: "docs/new name.md", OrigPath: "docs/old name.md"}},
+		},
+		{
+			name:   "copy",
+			output: "2 C." + renamed + "C75 copy.md\x00orig.md\x00",
+			want:   []Change{{Kind: Added, Path: "copy.md"}},
+		},
+		{
+			name: "unmerged",
+			output: "u UU N... 100644 100644 100644 100644 " +
+				"0123456789abcdef0123456789abcdef01234567 0123456789abcdef0123456789abcdef01234567 " +
+				"0123456789abcdef0123456789abcdef01234567 conflict.go\x00",
+			want: []Change{{Kind: Modified, Path: "conflict.go"}},
+		},
+		{
+			name:   "headers and ignored files are skipped",
+			output: "# branch.oid abc\x00! bin/releaseo\x00",
+		},
+		{
+			name:    "rename without original path",
+			output:  "2 R." + renamed + "R100 new.md",
+			wantErr: true,
+		},
+		{
+			name:    "truncated entry",
+			output:  "1 .M N...\x00",
+			wantErr: true,
+		},
+		{
+			name:    "unknown entry",
+			output:  "x what\x00",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := ParseStatus([]byte(tt.output))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseStatus() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseStatus() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPaths(t *testing.T) {
+	t.Parallel()
+
+	written, deleted := Paths([]Change{
+		{Kind: Modified, Path: "a"},
+		{Kind: Added, Path: "b"},
+		{Kind: Deleted, Path: "c"},
+		{Kind: Renamed, Path: "e", OrigPath: "d"},
+	})
+
+	if got := strings.Join(written, ","); got != "a,b,e" {
+		t.Errorf("written = %q, want a,b,e", got)
+	}
+	if got := strings.Join(deleted, ","); got != "c,d" {
+		t.Errorf("deleted = %q, want c,d", got)
+	}
+}
+
+func TestStatus(t *testing.T) {
+	t.Parallel()
+
+	repo := newTestRepo(t)
+	repo.write("VERSION", "1.0.0\n")
+	repo.write("old name.md", "old\n")
+	repo.write("gone.md", "gone\n")
+	repo.git("add", ".")
+	repo.git("commit", "-q", "-m", "initial")
+
+	repo.write("VERSION", "1.0.1\n")
+	repo.write("charts/new/README.md", "new\n")
+	if err := os.Remove(filepath.Join(repo.dir, "gone.md")); err != nil {
+		t.Fatal(err)
+	}
+	repo.git("mv", "old name.md", "new name.md")
+
+	got, err := Status(repo.dir)
+	if err != nil {
+		t.Fatalf("Status() error = %v", err)
+	}
+
+	want := []Change{
+		{Kind: Modified, Path: "VERSION"},
+		{Kind: Deleted, Path: "gone.md"},
+		{Kind: Renamed, Path: "new name.md", OrigPath: "old name.md"},
+		{Kind: Added, Path: "charts/new/README.md"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Status() = %+v, want %+v", got, want)
+	}
+}
+
+func TestSnapshot_ChangesSince(t *testing.T) {
+	t.Parallel()
+
+	repo := newTestRepo(t)
+	for _, name := range []string{"untouched.md", "touched.md", "removed.md", "chmod.sh"} {
+		repo.write(name, name+"\n")
+	}
+	repo.git("add", ".")
+	repo.git("commit", "-q", "-m", "initial")
+
+	// Left behind by earlier workflow steps
+	repo.write("untouched.md", "dirty\n")
+	repo.write("touched.md", "dirty\n")
+	repo.write("leftover.txt", "leftover\n")
+	repo.write("chmod.sh", "dirty\n")
+
+	before, err := TakeSnapshot(repo.dir)
+	if err != nil {
+		t.Fatalf("TakeSnapshot() error = %v", err)
+	}
+
+	// Changes made by releaseo
+	repo.write("touched.md", "dirty and regenerated\n")
+	repo.write("generated.md", "generated\n")
+	if err := os.Remove(filepath.Join(repo.dir, "removed.md")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(filepath.Join(repo.dir, "chmod.sh"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	after, err := TakeSnapshot(repo.dir)
+	if err != nil {
+		t.Fatalf("TakeSnapshot() error = %v", err)
+	}
+
+	want := []Change{
+		{Kind: Modified, Path: "chmod.sh"},
+		{Kind: Deleted, Path: "removed.md"},
+		{Kind: Modified, Path: "touched.md"},
+		{Kind: Added, Path: "generated.md"},
+	}
+	if got := after.ChangesSince(before); !reflect.DeepEqual(got, want) {
+		t.Errorf("ChangesSince() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,202 @@
+// Copyright 2025 Stacklok, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package github
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/go-github/v60/github"
+	"golang.org/x/oauth2"
+)
+
+// AppConfig holds the credentials for authenticating as a GitHub App installation.
+type AppConfig struct {
+	AppID int64
+	// InstallationID is the installation to mint tokens for. If zero, the
+	// installation is discovered from Owner and Repo.
+	InstallationID int64
+	// PrivateKey is the PEM encoded RSA private key of the app (PKCS#1 or PKCS#8).
+	PrivateKey []byte
+	Owner      string
+	Repo       string
+}
+
+// Validate checks that the fields required for app authentication are set.
+func (c *AppConfig) Validate() error {
+	if c.AppID <= 0 {
+		return fmt.Errorf("app ID is required")
+	}
+	if len(c.PrivateKey) == 0 {
+		return fmt.Errorf("app private key is required")
+	}
+	if c.InstallationID == 0 && (c.Owner == "" || c.Repo == "") {
+		return fmt.Errorf("owner and repo are required to discover the app installation")
+	}
+	return nil
+}
+
+// WithAppAuth authenticates as a GitHub App installation instead of with a static token.
+// Installation tokens are minted on demand and refreshed before they expire.
+// PRs opened this way trigger workflows, unlike PRs opened with GITHUB_TOKEN,
+// and commits are attributed to the app.
+func WithAppAuth(cfg AppConfig) ClientOption {
+	return func(c *Client) {
+		c.app = &cfg
+	}
+}
+
+// jwtLifetime is how long app JWTs are valid for; GitHub allows at most 10 minutes.
+const jwtLifetime = 9 * time.Minute
+
+// jwtClockSkew backdates the JWT issue time to tolerate clock drift between runner and GitHub.
+const jwtClockSkew = time.Minute
+
+// parsePrivateKey parses a PEM encoded RSA private key in PKCS#1 or PKCS#8 form.
+func parsePrivateKey(data []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("app private key is not PEM encoded")
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parsing app private key: %w", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("app private key is not an RSA key")
+	}
+	return key, nil
+}
+
+// appJWT returns a signed RS256 JWT identifying the app, as described in
+// https://docs.github.com/apps/creating-github-apps/authenticating-with-a-github-app/generating-a-json-web-token-jwt-for-a-github-app
+func appJWT(appID int64, key *rsa.PrivateKey, now time.Time) (string, error) {
+	header := map[string]string{"alg": "RS256", "typ": "JWT"}
+	claims := map[string]any{
+		"iat": now.Add(-jwtClockSkew).Unix(),
+		"exp": now.Add(jwtLifetime).Unix(),
+		"iss": strconv.FormatInt(appID, 10),
+	}
+
+	headerJSON, err := json.Marshal(header)
+	if err != nil {
+		return "", err
+	}
+	claimsJSON, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	enc := base64.RawURLEncoding
+	signingInput := enc.EncodeToString(headerJSON) + "." + enc.EncodeToString(claimsJSON)
+
+	digest := sha256.Sum256([]byte(signingInput))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("signing app JWT: %w", err)
+	}
+	return signingInput + "." + enc.EncodeToString(sig), nil
+}
+
+// jwtTransport authenticates every request with a freshly signed app JWT.
+type jwtTransport struct {
+	base  http.RoundTripper
+	appID int64
+	key   *rsa.PrivateKey
+}
+
+// RoundTrip adds the app JWT to the request and sends it with the base transport.
+func (t *jwtTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := appJWT(t.appID, t.key, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+token)
+	return t.base.RoundTrip(req)
+}
+
+// installationTokenSource mints installation access tokens for the app.
+type installationTokenSource struct {
+	ctx            context.Context
+	apps           *github.AppsService
+	installationID int64
+}
+
+// Token mints a new installation token. Wrapped in oauth2.ReuseTokenSource,
+// it is only called when the previous token is about to expire.
+func (s *installationTokenSource) Token() (*oauth2.Token, error) {
+	token, _, err := s.apps.CreateInstallationToken(s.ctx, s.installationID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating installation token for installation %d: %w", s.installationID, err)
+	}
+	return &oauth2.Token{
+		AccessToken: token.GetToken(),
+		Expiry:      token.GetExpiresAt().Time,
+	}, nil
+}
+
+// newAppTokenSource returns a token source for the configured app installation,
+// discovering the installation for the repository if no ID was given.
+func (c *Client) newAppTokenSource(ctx context.Context, base *http.Client) (oauth2.TokenSource, error) {
+	if err := c.app.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid GitHub App configuration: %w", err)
+	}
+
+	key, err := parsePrivateKey(c.app.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	appClient, err := c.newGitHubClient(&http.Client{
+		Transport: &jwtTransport{base: base.Transport, appID: c.app.AppID, key: key},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	installationID := c.app.InstallationID
+	if installationID == 0 {
+		installation, _, err := appClient.Apps.FindRepositoryInstallation(ctx, c.app.Owner, c.app.Repo)
+		if err != nil {
+			return nil, fmt.Errorf("finding app installation for %s/%s on %s: %w",
+				c.app.Owner, c.app.Repo, appClient.BaseURL.Host, err)
+		}
+		installationID = installation.GetID()
+	}
+
+	return oauth2.ReuseTokenSource(nil, &installationTokenSource{
+		ctx:            ctx,
+		apps:           appClient.Apps,
+		installationID: installationID,
+	}), nil
+}
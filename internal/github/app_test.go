@@ -0,0 +1,254 @@
+// Copyright 2025 Stacklok, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package github
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testAppKey is shared by the app tests; generating RSA keys is slow.
+var testAppKey = func() *rsa.PrivateKey {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		panic(err)
+	}
+	return key
+}()
+
+func pkcs1PEM(key *rsa.PrivateKey) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+}
+
+// verifyJWT checks the RS256 signature of token and returns its claims.
+func verifyJWT(t *testing.T, token string, pub *rsa.PublicKey) map[string]any {
+	t.Helper()
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		t.Fatalf("JWT has %d parts, want 3", len(parts))
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		t.Fatalf("decoding signature: %v", err)
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(pub, crypto.SHA256, digest[:], sig); err != nil {
+		t.Fatalf("JWT signature invalid: %v", err)
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		t.Fatalf("decoding claims: %v", err)
+	}
+	var claims map[string]any
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		t.Fatalf("unmarshalling claims: %v", err)
+	}
+	return claims
+}
+
+func TestAppConfig_Validate(t *testing.T) {
+	t.Parallel()
+
+	key := []byte("key")
+	tests := []struct {
+		name    string
+		cfg     AppConfig
+		wantErr bool
+	}{
+		{name: "installation ID", cfg: AppConfig{AppID: 1, InstallationID: 2, PrivateKey: key}},
+		{name: "discover from repo", cfg: AppConfig{AppID: 1, PrivateKey: key, Owner: "o", Repo: "r"}},
+		{name: "missing app ID", cfg: AppConfig{InstallationID: 2, PrivateKey: key}, wantErr: true},
+		{name: "missing key", cfg: AppConfig{AppID: 1, InstallationID: 2}, wantErr: true},
+		{name: "cannot discover", cfg: AppConfig{AppID: 1, PrivateKey: key, Owner: "o"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := tt.cfg.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParsePrivateKey(t *testing.T) {
+	t.Parallel()
+
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(testAppKey)
+	if err != nil {
+		t.Fatalf("marshalling PKCS#8 key: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		data    []byte
+		wantErr bool
+	}{
+		{name: "PKCS#1", data: pkcs1PEM(testAppKey)},
+		{name: "PKCS#8", data: pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8})},
+		{name: "not PEM", data: []byte("not a key"), wantErr: true},
+		{name: "garbage block", data: pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: []byte("x")}), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			key, err := parsePrivateKey(tt.data)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parsePrivateKey() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !key.Equal(testAppKey) {
+				t.Error("parsePrivateKey() returned a different key")
+			}
+		})
+	}
+}
+
+func TestAppJWT(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(1700000000, 0)
+	token, err := appJWT(12345, testAppKey, now)
+	if err != nil {
+		t.Fatalf("appJWT() error = %v", err)
+	}
+
+	claims := verifyJWT(t, token, &testAppKey.PublicKey)
+	if claims["iss"] != "12345" {
+		t.Errorf("iss = %v, want 12345", claims["iss"])
+	}
+	if got := int64(claims["iat"].(float64)); got != now.Add(-jwtClockSkew).Unix() {
+		t.Errorf("iat = %d, want %d", got, now.Add(-jwtClockSkew).Unix())
+	}
+	if got := int64(claims["exp"].(float64)); got != now.Add(jwtLifetime).Unix() {
+		t.Errorf("exp = %d, want %d", got, now.Add(jwtLifetime).Unix())
+	}
+}
+
+func TestNewClient_AppAuth(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		installationID int64
+		wantDiscovery  bool
+	}{
+		{name: "explicit installation", installationID: 42},
+		{name: "discovered installation", wantDiscovery: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var discovered, minted atomic.Int32
+			checkJWT := func(r *http.Request) {
+				token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+				if claims := verifyJWT(t, token, &testAppKey.PublicKey); claims["iss"] != "7" {
+					t.Errorf("iss = %v, want 7", claims["iss"])
+				}
+			}
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("GET /api/v3/repos/acme/widgets/installation", func(w http.ResponseWriter, r *http.Request) {
+				checkJWT(r)
+				discovered.Add(1)
+				fmt.Fprint(w, `{"id": 42}`)
+			})
+			mux.HandleFunc("POST /api/v3/app/installations/42/access_tokens", func(w http.ResponseWriter, r *http.Request) {
+				checkJWT(r)
+				n := minted.Add(1)
+				// The first token is already expired so the second request must refresh it.
+				expires := time.Now().Add(-time.Minute)
+				if n > 1 {
+					expires = time.Now().Add(time.Hour)
+				}
+				fmt.Fprintf(w, `{"token": "ghs_%d", "expires_at": %q}`, n, expires.UTC().Format(time.RFC3339))
+			})
+			var tokens []string
+			mux.HandleFunc("GET /api/v3/repos/acme/widgets", func(w http.ResponseWriter, r *http.Request) {
+				tokens = append(tokens, r.Header.Get("Authorization"))
+				fmt.Fprint(w, `{"name": "widgets"}`)
+			})
+			server := httptest.NewServer(mux)
+			t.Cleanup(server.Close)
+
+			ctx := context.Background()
+			c, err := NewClient(ctx, "", WithBaseURL(server.URL+"/api/v3/"), WithAppAuth(AppConfig{
+				AppID:          7,
+				InstallationID: tt.installationID,
+				PrivateKey:     pkcs1PEM(testAppKey),
+				Owner:          "acme",
+				Repo:           "widgets",
+			}))
+			if err != nil {
+				t.Fatalf("NewClient() error = %v", err)
+			}
+
+			for range 2 {
+				if _, _, err := c.client.Repositories.Get(ctx, "acme", "widgets"); err != nil {
+					t.Fatalf("Repositories.Get() error = %v", err)
+				}
+			}
+
+			if got := discovered.Load() == 1; got != tt.wantDiscovery {
+				t.Errorf("installation discovered = %v, want %v", got, tt.wantDiscovery)
+			}
+			want := []string{"Bearer ghs_1", "Bearer ghs_2"}
+			if strings.Join(tokens, ",") != strings.Join(want, ",") {
+				t.Errorf("request tokens = %v, want %v", tokens, want)
+			}
+		})
+	}
+}
+
+func TestNewClient_AppAuthErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		cfg  AppConfig
+	}{
+		{name: "invalid config", cfg: AppConfig{AppID: 1}},
+		{name: "invalid key", cfg: AppConfig{AppID: 1, InstallationID: 2, PrivateKey: []byte("nope")}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if _, err := NewClient(context.Background(), "", WithAppAuth(tt.cfg)); err == nil {
+				t.Error("NewClient() expected error, got nil")
+			}
+		})
+	}
+}
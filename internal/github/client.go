@@ -17,27 +17,55 @@ package github
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
+	"io/fs"
+	"net/http"
+	"net/url"
 	"os"
+	"strings"
 
 	"github.com/google/go-github/v60/github"
 	"golang.org/x/oauth2"
 )
 
+// DefaultAPIURL is the REST API endpoint of github.com.
+const DefaultAPIURL = "https://api.github.com/"
+
 // PRCreator defines the interface for creating pull requests.
 type PRCreator interface {
 	// CreateReleasePR creates a new branch with the modified files and opens a PR.
 	CreateReleasePR(ctx context.Context, req PRRequest) (*PRResult, error)
 }
 
-// Client wraps the GitHub API client and implements PRCreator.
+// PRMerger defines the interface for merging release pull requests.
+type PRMerger interface {
+	// MergeReleasePR enables auto-merge on the PR, or merges it once its checks pass.
+	MergeReleasePR(ctx context.Context, req MergeRequest) (*MergeResult, error)
+}
+
+// Client wraps the GitHub API client and implements PRCreator and PRMerger.
 type Client struct {
 	client     *github.Client
 	fileReader FileReader
+
+	// Connection settings, applied by NewClient after all options have run.
+	apiURL       string
+	uploadURL    string
+	caBundlePath string
+	app          *AppConfig
+	retry        RetryConfig
 }
 
-// Ensure Client implements PRCreator at compile time.
-var _ PRCreator = (*Client)(nil)
+// Ensure Client implements PRCreator and PRMerger at compile time.
+var (
+	_ PRCreator = (*Client)(nil)
+	_ PRMerger  = (*Client)(nil)
+)
+
+// Ensure osFileReader implements FileInfoReader at compile time.
+var _ FileInfoReader = (*osFileReader)(nil)
 
 // osFileReader is the default FileReader implementation that uses os.ReadFile.
 type osFileReader struct{}
@@ -47,6 +75,16 @@ func (*osFileReader) ReadFile(path string) ([]byte, error) {
 	return os.ReadFile(path)
 }
 
+// Lstat returns the file info of path using os.Lstat.
+func (*osFileReader) Lstat(path string) (fs.FileInfo, error) {
+	return os.Lstat(path)
+}
+
+// Readlink returns the symlink target of path using os.Readlink.
+func (*osFileReader) Readlink(path string) (string, error) {
+	return os.Readlink(path)
+}
+
 // ClientOption is a functional option for configuring the Client.
 type ClientOption func(*Client)
 
@@ -58,20 +96,36 @@ func WithFileReader(fr FileReader) ClientOption {
 	}
 }
 
-// NewClient creates a new GitHub client with the provided token.
-// Optional ClientOption functions can be provided to customize the client behavior.
-func NewClient(ctx context.Context, token string, opts ...ClientOption) (*Client, error) {
-	if token == "" {
-		return nil, fmt.Errorf("token is required")
+// WithBaseURL sets the REST API base URL, for GitHub Enterprise Server
+// (e.g., "https://github.example.com/api/v3"). The "/api/v3" suffix is added if missing.
+// An empty URL or the github.com API URL keeps the default.
+func WithBaseURL(apiURL string) ClientOption {
+	return func(c *Client) {
+		c.apiURL = apiURL
 	}
+}
+
+// WithUploadURL sets the uploads API URL for GitHub Enterprise Server.
+// If unset, it is derived from the base URL.
+func WithUploadURL(uploadURL string) ClientOption {
+	return func(c *Client) {
+		c.uploadURL = uploadURL
+	}
+}
 
-	ts := oauth2.StaticTokenSource(
-		&oauth2.Token{AccessToken: token},
-	)
-	tc := oauth2.NewClient(ctx, ts)
+// WithCABundle adds the PEM encoded certificates in the file at path to the
+// system trust store, for GitHub Enterprise Server instances using a private CA.
+func WithCABundle(path string) ClientOption {
+	return func(c *Client) {
+		c.caBundlePath = path
+	}
+}
 
+// NewClient creates a new GitHub client with the provided token.
+// The token may be empty when WithAppAuth is used.
+// Optional ClientOption functions can be provided to customize the client behavior.
+func NewClient(ctx context.Context, token string, opts ...ClientOption) (*Client, error) {
 	c := &Client{
-		client:     github.NewClient(tc),
 		fileReader: &osFileReader{},
 	}
 
@@ -79,22 +133,134 @@ func NewClient(ctx context.Context, token string, opts ...ClientOption) (*Client
 		opt(c)
 	}
 
+	if token == "" && c.app == nil {
+		return nil, fmt.Errorf("token is required")
+	}
+
+	var transport http.RoundTripper = http.DefaultTransport
+	if c.caBundlePath != "" {
+		var err error
+		transport, err = newCATransport(c.caBundlePath)
+		if err != nil {
+			return nil, err
+		}
+	}
+	base := &http.Client{Transport: newRetryTransport(transport, c.retry)}
+	// oauth2 uses the HTTP client stored in the context as its base transport
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, base)
+
+	var ts oauth2.TokenSource
+	if c.app != nil {
+		var err error
+		ts, err = c.newAppTokenSource(ctx, base)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		ts = oauth2.StaticTokenSource(
+			&oauth2.Token{AccessToken: token},
+		)
+	}
+
+	gh, err := c.newGitHubClient(oauth2.NewClient(ctx, ts))
+	if err != nil {
+		return nil, err
+	}
+	c.client = gh
+
 	return c, nil
 }
 
+// newGitHubClient returns a go-github client using httpClient, pointed at the configured API URLs.
+func (c *Client) newGitHubClient(httpClient *http.Client) (*github.Client, error) {
+	gh := github.NewClient(httpClient)
+	if isDefaultAPIURL(c.apiURL) {
+		return gh, nil
+	}
+
+	uploadURL := c.uploadURL
+	if uploadURL == "" {
+		uploadURL = strings.TrimSuffix(strings.TrimSuffix(c.apiURL, "/"), "/api/v3") + "/api/uploads/"
+	}
+	gh, err := gh.WithEnterpriseURLs(c.apiURL, uploadURL)
+	if err != nil {
+		return nil, fmt.Errorf("configuring GitHub Enterprise URL %s: %w", c.apiURL, err)
+	}
+	return gh, nil
+}
+
+// isDefaultAPIURL reports whether apiURL selects the github.com API.
+func isDefaultAPIURL(apiURL string) bool {
+	return apiURL == "" || strings.TrimSuffix(apiURL, "/") == strings.TrimSuffix(DefaultAPIURL, "/")
+}
+
+// newCATransport returns an HTTP transport trusting the system roots plus the certificates in caBundlePath.
+func newCATransport(caBundlePath string) (*http.Transport, error) {
+	pem, err := os.ReadFile(caBundlePath)
+	if err != nil {
+		return nil, fmt.Errorf("reading CA bundle %s: %w", caBundlePath, err)
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no PEM certificates found in CA bundle %s", caBundlePath)
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{
+		RootCAs:    pool,
+		MinVersion: tls.VersionTLS12,
+	}
+	return transport, nil
+}
+
+// Host returns the host name of the GitHub API the client talks to.
+func (c *Client) Host() string {
+	return c.client.BaseURL.Host
+}
+
+// webURL returns the web UI root for the configured API, e.g. "https://github.com"
+// for api.github.com or "https://github.example.com" for ".../api/v3/".
+func (c *Client) webURL() string {
+	u := *c.client.BaseURL
+	u.Path = strings.TrimSuffix(strings.TrimSuffix(u.Path, "/"), "/api/v3")
+	u.Host = strings.TrimPrefix(u.Host, "api.")
+	return strings.TrimSuffix(u.String(), "/")
+}
+
+// pullRequestURL returns the web URL of a pull request on the configured host.
+func (c *Client) pullRequestURL(owner, repo string, number int) string {
+	return fmt.Sprintf("%s/%s/%s/pull/%d", c.webURL(), url.PathEscape(owner), url.PathEscape(repo), number)
+}
+
 // PRRequest contains the parameters for creating a pull request.
-// All fields except Body and TriggeredBy are required.
+// Owner, Repo, BaseBranch, HeadBranch, Title and at least one file change are required.
 type PRRequest struct {
-	Owner       string   // GitHub repository owner (required)
-	Repo        string   // GitHub repository name (required)
-	BaseBranch  string   // Base branch for the PR (required, e.g., "main")
-	HeadBranch  string   // Feature branch to create (required)
-	Title       string   // PR title (required)
-	Body        string   // PR body/description
-	Files       []string // Files to commit (required, must not be empty)
-	TriggeredBy string   // GitHub actor who triggered the release (optional, added as git trailer)
+	Owner         string   // GitHub repository owner (required)
+	Repo          string   // GitHub repository name (required)
+	BaseBranch    string   // Base branch for the PR (required, e.g., "main")
+	HeadBranch    string   // Feature branch to create (required)
+	Title         string   // PR title (required)
+	Body          string   // PR body/description
+	CommitMessage string   // Commit message (optional, defaults to DefaultCommitMessage)
+	Files         []string // Files to commit (required unless DeletedFiles is set)
+	DeletedFiles  []string // Files to delete in the commit
+	TriggeredBy   string   // GitHub actor who triggered the release (optional, added as git trailer)
+	Draft         bool     // Open the PR as a draft
+	Labels        []string // Labels to add; missing labels are created with LabelColor
+	LabelColor    string   // Colour for created labels (optional, defaults to DefaultLabelColor)
+	Reviewers     []string // Users to request a review from
+	TeamReviewers []string // Team slugs to request a review from
+	Assignees     []string // Users to assign
+	Milestone     string   // Milestone title to attach; created if it does not exist
 }
 
+// DefaultCommitMessage is the commit message used when PRRequest.CommitMessage is empty.
+const DefaultCommitMessage = "Update release files"
+
 // Validate checks that all required fields are set.
 func (r *PRRequest) Validate() error {
 	if r.Owner == "" {
@@ -112,9 +278,14 @@ func (r *PRRequest) Validate() error {
 	if r.Title == "" {
 		return fmt.Errorf("title is required")
 	}
-	if len(r.Files) == 0 {
+	if len(r.Files) == 0 && len(r.DeletedFiles) == 0 {
 		return fmt.Errorf("at least one file is required")
 	}
+	if r.LabelColor != "" {
+		if err := ValidateLabelColor(r.LabelColor); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -122,4 +293,7 @@ func (r *PRRequest) Validate() error {
 type PRResult struct {
 	Number int
 	URL    string
+	// Warnings lists non-fatal failures that occurred after the PR was created,
+	// such as a reviewer that could not be requested.
+	Warnings []string
 }
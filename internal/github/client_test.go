@@ -16,6 +16,11 @@ package github
 
 import (
 	"context"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
 )
 
@@ -112,6 +117,11 @@ func TestPRRequest_Validate(t *testing.T) {
 			modify:  func(r *PRRequest) { r.Files = []string{} },
 			wantErr: "at least one file is required",
 		},
+		{
+			name:    "deletions only",
+			modify:  func(r *PRRequest) { r.Files = nil; r.DeletedFiles = []string{"old.yaml"} },
+			wantErr: "",
+		},
 		{
 			name:    "body is optional",
 			modify:  func(r *PRRequest) { r.Body = "" },
@@ -264,12 +274,12 @@ func TestDeduplicateFiles(t *testing.T) {
 }
 
 // TestCommitMessageFormat tests the commit message format with and without git trailer.
-// This tests the format logic used in commitFiles().
 func TestCommitMessageFormat(t *testing.T) {
 	t.Parallel()
 
 	tests := []struct {
 		name        string
+		message     string
 		triggeredBy string
 		wantMessage string
 	}{
@@ -288,17 +298,24 @@ func TestCommitMessageFormat(t *testing.T) {
 			triggeredBy: "releasebot",
 			wantMessage: "Update release files\n\nRelease-Triggered-By: releasebot",
 		},
+		{
+			name:        "custom message without triggered by",
+			message:     "chore(release): 1.2.3\n\nRefs: REL-42",
+			wantMessage: "chore(release): 1.2.3\n\nRefs: REL-42",
+		},
+		{
+			name:        "custom message with triggered by",
+			message:     "chore(release): 1.2.3",
+			triggeredBy: "testuser",
+			wantMessage: "chore(release): 1.2.3\n\nRelease-Triggered-By: testuser",
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 
-			// Replicate the message format logic from commitFiles()
-			message := "Update release files"
-			if tt.triggeredBy != "" {
-				message += "\n\nRelease-Triggered-By: " + tt.triggeredBy
-			}
+			message := buildCommitMessage(tt.message, tt.triggeredBy)
 
 			if message != tt.wantMessage {
 				t.Errorf("commit message = %q, want %q", message, tt.wantMessage)
@@ -306,3 +323,148 @@ func TestCommitMessageFormat(t *testing.T) {
 		})
 	}
 }
+
+func TestNewClient_EnterpriseURLs(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		opts       []ClientOption
+		wantBase   string
+		wantUpload string
+		wantWeb    string
+	}{
+		{
+			name:       "default github.com",
+			wantBase:   "https://api.github.com/",
+			wantUpload: "https://uploads.github.com/",
+			wantWeb:    "https://github.com",
+		},
+		{
+			name:       "explicit github.com API URL",
+			opts:       []ClientOption{WithBaseURL("https://api.github.com")},
+			wantBase:   "https://api.github.com/",
+			wantUpload: "https://uploads.github.com/",
+			wantWeb:    "https://github.com",
+		},
+		{
+			name:       "GHES API URL with suffix",
+			opts:       []ClientOption{WithBaseURL("https://ghes.example.com/api/v3")},
+			wantBase:   "https://ghes.example.com/api/v3/",
+			wantUpload: "https://ghes.example.com/api/uploads/",
+			wantWeb:    "https://ghes.example.com",
+		},
+		{
+			name:       "GHES server URL without suffix",
+			opts:       []ClientOption{WithBaseURL("https://ghes.example.com/")},
+			wantBase:   "https://ghes.example.com/api/v3/",
+			wantUpload: "https://ghes.example.com/api/uploads/",
+			wantWeb:    "https://ghes.example.com",
+		},
+		{
+			name: "explicit upload URL",
+			opts: []ClientOption{
+				WithBaseURL("https://ghes.example.com/api/v3"),
+				WithUploadURL("https://uploads.ghes.example.com/api/uploads"),
+			},
+			wantBase:   "https://ghes.example.com/api/v3/",
+			wantUpload: "https://uploads.ghes.example.com/api/uploads/",
+			wantWeb:    "https://ghes.example.com",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			client, err := NewClient(context.Background(), "test-token", tt.opts...)
+			if err != nil {
+				t.Fatalf("NewClient() unexpected error = %v", err)
+			}
+			if got := client.client.BaseURL.String(); got != tt.wantBase {
+				t.Errorf("BaseURL = %q, want %q", got, tt.wantBase)
+			}
+			if got := client.client.UploadURL.String(); got != tt.wantUpload {
+				t.Errorf("UploadURL = %q, want %q", got, tt.wantUpload)
+			}
+			if got := client.webURL(); got != tt.wantWeb {
+				t.Errorf("webURL() = %q, want %q", got, tt.wantWeb)
+			}
+		})
+	}
+}
+
+func TestClient_PullRequestURL(t *testing.T) {
+	t.Parallel()
+
+	client, err := NewClient(context.Background(), "test-token", WithBaseURL("https://ghes.example.com/api/v3"))
+	if err != nil {
+		t.Fatalf("NewClient() unexpected error = %v", err)
+	}
+	want := "https://ghes.example.com/owner/repo/pull/12"
+	if got := client.pullRequestURL("owner", "repo", 12); got != want {
+		t.Errorf("pullRequestURL() = %q, want %q", got, want)
+	}
+	if got := client.Host(); got != "ghes.example.com" {
+		t.Errorf("Host() = %q, want %q", got, "ghes.example.com")
+	}
+}
+
+func TestNewClient_CABundle(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test-token" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{"ref":"refs/heads/main","object":{"sha":"abc"}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	dir := t.TempDir()
+	caPath := filepath.Join(dir, "ca.pem")
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(caPath, caPEM, 0600); err != nil {
+		t.Fatalf("writing CA bundle: %v", err)
+	}
+	invalidPath := filepath.Join(dir, "invalid.pem")
+	if err := os.WriteFile(invalidPath, []byte("not a certificate"), 0600); err != nil {
+		t.Fatalf("writing invalid CA bundle: %v", err)
+	}
+
+	t.Run("trusted private CA", func(t *testing.T) {
+		t.Parallel()
+		client, err := NewClient(context.Background(), "test-token", WithBaseURL(server.URL+"/api/v3"), WithCABundle(caPath))
+		if err != nil {
+			t.Fatalf("NewClient() unexpected error = %v", err)
+		}
+		if _, _, err := client.client.Git.GetRef(context.Background(), "o", "r", "refs/heads/main"); err != nil {
+			t.Errorf("GetRef() over TLS with private CA failed: %v", err)
+		}
+	})
+
+	t.Run("untrusted without CA bundle", func(t *testing.T) {
+		t.Parallel()
+		client, err := NewClient(context.Background(), "test-token", WithBaseURL(server.URL+"/api/v3"))
+		if err != nil {
+			t.Fatalf("NewClient() unexpected error = %v", err)
+		}
+		if _, _, err := client.client.Git.GetRef(context.Background(), "o", "r", "refs/heads/main"); err == nil {
+			t.Error("GetRef() succeeded without trusting the private CA")
+		}
+	})
+
+	t.Run("missing bundle", func(t *testing.T) {
+		t.Parallel()
+		if _, err := NewClient(context.Background(), "test-token", WithCABundle(filepath.Join(dir, "missing.pem"))); err == nil {
+			t.Error("NewClient() expected error for missing CA bundle")
+		}
+	})
+
+	t.Run("bundle without certificates", func(t *testing.T) {
+		t.Parallel()
+		if _, err := NewClient(context.Background(), "test-token", WithCABundle(invalidPath)); err == nil {
+			t.Error("NewClient() expected error for CA bundle without certificates")
+		}
+	})
+}
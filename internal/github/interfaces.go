@@ -14,6 +14,8 @@
 
 package github
 
+import "io/fs"
+
 // FileReader defines the interface for reading file contents.
 // This abstraction allows for dependency injection and makes the client
 // testable by enabling mock file systems.
@@ -22,3 +24,14 @@ type FileReader interface {
 	// It returns the file contents as a byte slice, or an error if the read fails.
 	ReadFile(path string) ([]byte, error)
 }
+
+// FileInfoReader is an optional extension of FileReader that reports file modes
+// and symlink targets, so that commits preserve executable bits and symlinks.
+// Files read through a FileReader that does not implement it are committed as
+// regular, non-executable files.
+type FileInfoReader interface {
+	// Lstat returns the file info of path without following symlinks.
+	Lstat(path string) (fs.FileInfo, error)
+	// Readlink returns the target of the symlink at path.
+	Readlink(path string) (string, error)
+}
@@ -0,0 +1,325 @@
+// Copyright 2025 Stacklok, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v60/github"
+)
+
+// Merge methods supported for release PRs.
+const (
+	MergeMethodMerge  = "merge"
+	MergeMethodSquash = "squash"
+	MergeMethodRebase = "rebase"
+)
+
+// Merge statuses reported in MergeResult.Status.
+const (
+	// MergeStatusAutoMergeEnabled means GitHub will merge the PR once its requirements are met.
+	MergeStatusAutoMergeEnabled = "auto-merge-enabled"
+	// MergeStatusMerged means releaseo merged the PR itself after its checks passed.
+	MergeStatusMerged = "merged"
+	// MergeStatusChecksFailed means at least one required check failed, so the PR was left open.
+	MergeStatusChecksFailed = "checks-failed"
+	// MergeStatusTimedOut means the checks did not complete before the timeout, so the PR was left open.
+	MergeStatusTimedOut = "timed-out"
+)
+
+// DefaultMergePollInterval is how often check status is polled when auto-merge is unavailable.
+const DefaultMergePollInterval = 30 * time.Second
+
+// ValidateMergeMethod checks that method is one of the supported merge methods.
+func ValidateMergeMethod(method string) error {
+	switch method {
+	case MergeMethodMerge, MergeMethodSquash, MergeMethodRebase:
+		return nil
+	default:
+		return fmt.Errorf("invalid merge method %q (expected %s, %s or %s)",
+			method, MergeMethodMerge, MergeMethodSquash, MergeMethodRebase)
+	}
+}
+
+// MergeRequest contains the parameters for merging a release PR.
+type MergeRequest struct {
+	Owner        string        // GitHub repository owner (required)
+	Repo         string        // GitHub repository name (required)
+	Number       int           // PR number (required)
+	Method       string        // Merge method: merge, squash or rebase (required)
+	Timeout      time.Duration // How long to wait for checks when falling back to a direct merge (required)
+	PollInterval time.Duration // How often to poll checks (optional, defaults to DefaultMergePollInterval)
+}
+
+// Validate checks that all required fields are set.
+func (r *MergeRequest) Validate() error {
+	if r.Owner == "" {
+		return fmt.Errorf("owner is required")
+	}
+	if r.Repo == "" {
+		return fmt.Errorf("repo is required")
+	}
+	if r.Number <= 0 {
+		return fmt.Errorf("PR number is required")
+	}
+	if r.Timeout <= 0 {
+		return fmt.Errorf("timeout must be positive")
+	}
+	return ValidateMergeMethod(r.Method)
+}
+
+// MergeResult contains the outcome of merging a release PR.
+type MergeResult struct {
+	Status string // One of the MergeStatus constants
+	SHA    string // Merge commit SHA, set when Status is MergeStatusMerged
+	Reason string // Human readable explanation for the status
+}
+
+// MergeReleasePR enables GitHub auto-merge on the PR. If auto-merge cannot be enabled
+// (e.g., it is disabled for the repository), it falls back to polling the PR's checks
+// and merging directly once they pass, giving up after req.Timeout.
+func (c *Client) MergeReleasePR(ctx context.Context, req MergeRequest) (*MergeResult, error) {
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid merge request: %w", err)
+	}
+
+	pr, _, err := c.client.PullRequests.Get(ctx, req.Owner, req.Repo, req.Number)
+	if err != nil {
+		return nil, fmt.Errorf("getting pull request: %w", err)
+	}
+
+	autoMergeErr := c.enableAutoMerge(ctx, pr.GetNodeID(), req.Method)
+	if autoMergeErr == nil {
+		return &MergeResult{
+			Status: MergeStatusAutoMergeEnabled,
+			Reason: fmt.Sprintf("auto-merge (%s) enabled on PR #%d", req.Method, req.Number),
+		}, nil
+	}
+	fmt.Printf("Could not enable auto-merge on PR #%d (%v), waiting for checks to merge directly\n", req.Number, autoMergeErr)
+
+	return c.mergeWhenGreen(ctx, req, pr)
+}
+
+// enableAutoMergeMutation is the GraphQL mutation enabling auto-merge; there is no REST equivalent.
+const enableAutoMergeMutation = `mutation($id: ID!, $method: PullRequestMergeMethod!) {
+  enablePullRequestAutoMerge(input: {pullRequestId: $id, mergeMethod: $method}) { clientMutationId }
+}`
+
+// graphQLResponse is the subset of a GraphQL response needed to detect errors.
+type graphQLResponse struct {
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// enableAutoMerge enables auto-merge on the PR with the given GraphQL node ID.
+func (c *Client) enableAutoMerge(ctx context.Context, nodeID, method string) error {
+	body := map[string]any{
+		"query": enableAutoMergeMutation,
+		"variables": map[string]string{
+			"id":     nodeID,
+			"method": strings.ToUpper(method),
+		},
+	}
+
+	httpReq, err := c.client.NewRequest("POST", c.graphQLURL(), body)
+	if err != nil {
+		return fmt.Errorf("building GraphQL request: %w", err)
+	}
+
+	var resp graphQLResponse
+	if _, err := c.client.Do(ctx, httpReq, &resp); err != nil {
+		return err
+	}
+	if len(resp.Errors) > 0 {
+		msgs := make([]string, 0, len(resp.Errors))
+		for _, e := range resp.Errors {
+			msgs = append(msgs, e.Message)
+		}
+		return errors.New(strings.Join(msgs, "; "))
+	}
+	return nil
+}
+
+// graphQLURL returns the GraphQL endpoint for the configured REST API base URL.
+// GitHub Enterprise Server serves REST under /api/v3/ and GraphQL under /api/graphql.
+func (c *Client) graphQLURL() string {
+	base := c.client.BaseURL.String()
+	if strings.HasSuffix(base, "/api/v3/") {
+		return strings.TrimSuffix(base, "v3/") + "graphql"
+	}
+	return base + "graphql"
+}
+
+// checkState is the aggregated state of a commit's checks.
+type checkState int
+
+const (
+	checksPending checkState = iota
+	checksPassed
+	checksFailed
+)
+
+// mergeWhenGreen polls the PR head's checks and merges the PR once they pass.
+func (c *Client) mergeWhenGreen(ctx context.Context, req MergeRequest, pr *github.PullRequest) (*MergeResult, error) {
+	interval := req.PollInterval
+	if interval <= 0 {
+		interval = DefaultMergePollInterval
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, req.Timeout)
+	defer cancel()
+
+	required := c.requiredChecks(ctx, req.Owner, req.Repo, pr.GetBase().GetRef())
+	sha := pr.GetHead().GetSHA()
+
+	for {
+		state, detail, err := c.checkState(ctx, req.Owner, req.Repo, sha, required)
+		if err != nil && ctx.Err() == nil {
+			return nil, err
+		}
+
+		switch state {
+		case checksFailed:
+			return &MergeResult{Status: MergeStatusChecksFailed, Reason: detail}, nil
+		case checksPassed:
+			result, _, err := c.client.PullRequests.Merge(ctx, req.Owner, req.Repo, req.Number, "",
+				&github.PullRequestOptions{MergeMethod: req.Method, SHA: sha})
+			if err != nil {
+				return nil, fmt.Errorf("merging pull request: %w", err)
+			}
+			return &MergeResult{
+				Status: MergeStatusMerged,
+				SHA:    result.GetSHA(),
+				Reason: fmt.Sprintf("merged PR #%d (%s) after checks passed", req.Number, req.Method),
+			}, nil
+		case checksPending:
+		}
+
+		select {
+		case <-ctx.Done():
+			return &MergeResult{
+				Status: MergeStatusTimedOut,
+				Reason: fmt.Sprintf("checks did not complete within %s: %s", req.Timeout, detail),
+			}, nil
+		case <-time.After(interval):
+		}
+	}
+}
+
+// requiredChecks returns the check names required by branch protection on the base branch.
+// It returns nil if they cannot be determined (no protection, or the token lacks access),
+// in which case every check reported on the commit is treated as required.
+func (c *Client) requiredChecks(ctx context.Context, owner, repo, branch string) []string {
+	checks, _, err := c.client.Repositories.GetRequiredStatusChecks(ctx, owner, repo, branch)
+	if err != nil {
+		return nil
+	}
+
+	var names []string
+	if checks.Checks != nil {
+		for _, check := range *checks.Checks {
+			names = append(names, check.Context)
+		}
+	}
+	if len(names) == 0 && checks.Contexts != nil {
+		names = append(names, *checks.Contexts...)
+	}
+	return names
+}
+
+// checkState aggregates commit statuses and check runs for sha into a single state,
+// along with a short description of what is pending or failing.
+func (c *Client) checkState(ctx context.Context, owner, repo, sha string, required []string) (checkState, string, error) {
+	states, err := c.commitCheckStates(ctx, owner, repo, sha)
+	if err != nil {
+		return checksPending, err.Error(), err
+	}
+
+	names := required
+	if len(names) == 0 {
+		if len(states) == 0 {
+			return checksPending, "no checks reported yet", nil
+		}
+		for name := range states {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+	}
+
+	var pending, failed []string
+	for _, name := range names {
+		state, ok := states[name]
+		switch {
+		case !ok || state == checksPending:
+			pending = append(pending, name)
+		case state == checksFailed:
+			failed = append(failed, name)
+		}
+	}
+
+	if len(failed) > 0 {
+		return checksFailed, "failed checks: " + strings.Join(failed, ", "), nil
+	}
+	if len(pending) > 0 {
+		return checksPending, "pending checks: " + strings.Join(pending, ", "), nil
+	}
+	return checksPassed, "all checks passed", nil
+}
+
+// commitCheckStates returns the state of every commit status and check run on sha, keyed by name.
+func (c *Client) commitCheckStates(ctx context.Context, owner, repo, sha string) (map[string]checkState, error) {
+	states := make(map[string]checkState)
+
+	combined, _, err := c.client.Repositories.GetCombinedStatus(ctx, owner, repo, sha, &github.ListOptions{PerPage: 100})
+	if err != nil {
+		return nil, fmt.Errorf("getting commit status: %w", err)
+	}
+	for _, status := range combined.Statuses {
+		switch status.GetState() {
+		case "success":
+			states[status.GetContext()] = checksPassed
+		case "pending":
+			states[status.GetContext()] = checksPending
+		default:
+			states[status.GetContext()] = checksFailed
+		}
+	}
+
+	runs, _, err := c.client.Checks.ListCheckRunsForRef(ctx, owner, repo, sha,
+		&github.ListCheckRunsOptions{ListOptions: github.ListOptions{PerPage: 100}})
+	if err != nil {
+		return nil, fmt.Errorf("listing check runs: %w", err)
+	}
+	for _, run := range runs.CheckRuns {
+		if run.GetStatus() != "completed" {
+			states[run.GetName()] = checksPending
+			continue
+		}
+		switch run.GetConclusion() {
+		case "success", "neutral", "skipped":
+			states[run.GetName()] = checksPassed
+		default:
+			states[run.GetName()] = checksFailed
+		}
+	}
+
+	return states, nil
+}
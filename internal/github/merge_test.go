@@ -0,0 +1,226 @@
+// Copyright 2025 Stacklok, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package github
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMergeRequest_Validate(t *testing.T) {
+	t.Parallel()
+
+	valid := MergeRequest{Owner: "o", Repo: "r", Number: 1, Method: MergeMethodSquash, Timeout: time.Minute}
+
+	tests := []struct {
+		name    string
+		modify  func(*MergeRequest)
+		wantErr bool
+	}{
+		{name: "valid", modify: func(_ *MergeRequest) {}},
+		{name: "missing owner", modify: func(r *MergeRequest) { r.Owner = "" }, wantErr: true},
+		{name: "missing number", modify: func(r *MergeRequest) { r.Number = 0 }, wantErr: true},
+		{name: "zero timeout", modify: func(r *MergeRequest) { r.Timeout = 0 }, wantErr: true},
+		{name: "invalid method", modify: func(r *MergeRequest) { r.Method = "fast-forward" }, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			req := valid
+			tt.modify(&req)
+			if err := req.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGraphQLURL(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		baseURL string
+		want    string
+	}{
+		{baseURL: "https://api.github.com/", want: "https://api.github.com/graphql"},
+		{baseURL: "https://ghes.example.com/api/v3/", want: "https://ghes.example.com/api/graphql"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.baseURL, func(t *testing.T) {
+			t.Parallel()
+			client := newTestClient(t, http.NewServeMux())
+			u, err := url.Parse(tt.baseURL)
+			if err != nil {
+				t.Fatalf("parsing URL: %v", err)
+			}
+			client.client.BaseURL = u
+			if got := client.graphQLURL(); got != tt.want {
+				t.Errorf("graphQLURL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// mergeTestServer returns a mux serving PR #7 with head SHA "abc" on base "main".
+func mergeTestServer(t *testing.T, log *requestLog, graphQLResponse string) *http.ServeMux {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/o/r/pulls/7", func(w http.ResponseWriter, r *http.Request) {
+		log.record(t, r)
+		_, _ = w.Write([]byte(`{"number":7,"node_id":"PR_node","head":{"sha":"abc"},"base":{"ref":"main"}}`))
+	})
+	mux.HandleFunc("/graphql", func(w http.ResponseWriter, r *http.Request) {
+		log.record(t, r)
+		_, _ = w.Write([]byte(graphQLResponse))
+	})
+	mux.HandleFunc("/repos/o/r/pulls/7/merge", func(w http.ResponseWriter, r *http.Request) {
+		log.record(t, r)
+		_, _ = w.Write([]byte(`{"sha":"merged123","merged":true}`))
+	})
+	return mux
+}
+
+func TestMergeReleasePR_AutoMergeEnabled(t *testing.T) {
+	t.Parallel()
+
+	log := &requestLog{}
+	mux := mergeTestServer(t, log, `{"data":{"enablePullRequestAutoMerge":{"clientMutationId":null}}}`)
+	client := newTestClient(t, mux)
+
+	result, err := client.MergeReleasePR(context.Background(), MergeRequest{
+		Owner: "o", Repo: "r", Number: 7, Method: MergeMethodSquash, Timeout: time.Minute,
+	})
+	if err != nil {
+		t.Fatalf("MergeReleasePR() unexpected error: %v", err)
+	}
+	if result.Status != MergeStatusAutoMergeEnabled {
+		t.Errorf("Status = %q, want %q", result.Status, MergeStatusAutoMergeEnabled)
+	}
+
+	vars, _ := log.bodies["POST /graphql"]["variables"].(map[string]any)
+	if vars["id"] != "PR_node" || vars["method"] != "SQUASH" {
+		t.Errorf("GraphQL variables = %v, want id PR_node and method SQUASH", vars)
+	}
+	if log.has("PUT /repos/o/r/pulls/7/merge") {
+		t.Error("PR should not be merged directly when auto-merge is enabled")
+	}
+}
+
+func TestMergeReleasePR_FallbackMergesWhenGreen(t *testing.T) {
+	t.Parallel()
+
+	log := &requestLog{}
+	mux := mergeTestServer(t, log, `{"errors":[{"message":"Auto merge is not allowed for this repository"}]}`)
+	mux.HandleFunc("/repos/o/r/branches/main/protection/required_status_checks", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"checks":[{"context":"test"}]}`))
+	})
+	mux.HandleFunc("/repos/o/r/commits/abc/status", func(w http.ResponseWriter, _ *http.Request) {
+		// Non-required statuses are ignored even when failing
+		_, _ = w.Write([]byte(`{"statuses":[{"context":"optional-lint","state":"failure"}]}`))
+	})
+	var polls atomic.Int32
+	mux.HandleFunc("/repos/o/r/commits/abc/check-runs", func(w http.ResponseWriter, _ *http.Request) {
+		if polls.Add(1) == 1 {
+			_, _ = w.Write([]byte(`{"check_runs":[{"name":"test","status":"in_progress"}]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"check_runs":[{"name":"test","status":"completed","conclusion":"success"}]}`))
+	})
+	client := newTestClient(t, mux)
+
+	result, err := client.MergeReleasePR(context.Background(), MergeRequest{
+		Owner: "o", Repo: "r", Number: 7, Method: MergeMethodRebase,
+		Timeout: 5 * time.Second, PollInterval: time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("MergeReleasePR() unexpected error: %v", err)
+	}
+	if result.Status != MergeStatusMerged || result.SHA != "merged123" {
+		t.Errorf("result = %+v, want merged with SHA merged123", result)
+	}
+	if polls.Load() < 2 {
+		t.Errorf("check runs polled %d times, want at least 2", polls.Load())
+	}
+	body := log.bodies["PUT /repos/o/r/pulls/7/merge"]
+	if body["merge_method"] != "rebase" || body["sha"] != "abc" {
+		t.Errorf("merge request body = %v, want merge_method rebase and sha abc", body)
+	}
+}
+
+func TestMergeReleasePR_FallbackChecksFailed(t *testing.T) {
+	t.Parallel()
+
+	log := &requestLog{}
+	mux := mergeTestServer(t, log, `{"errors":[{"message":"Auto merge is not allowed for this repository"}]}`)
+	mux.HandleFunc("/repos/o/r/branches/main/protection/required_status_checks", func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, `{"message":"Branch not protected"}`, http.StatusNotFound)
+	})
+	mux.HandleFunc("/repos/o/r/commits/abc/status", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"statuses":[{"context":"ci/build","state":"success"}]}`))
+	})
+	mux.HandleFunc("/repos/o/r/commits/abc/check-runs", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"check_runs":[{"name":"test","status":"completed","conclusion":"failure"}]}`))
+	})
+	client := newTestClient(t, mux)
+
+	result, err := client.MergeReleasePR(context.Background(), MergeRequest{
+		Owner: "o", Repo: "r", Number: 7, Method: MergeMethodMerge,
+		Timeout: 5 * time.Second, PollInterval: time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("MergeReleasePR() unexpected error: %v", err)
+	}
+	if result.Status != MergeStatusChecksFailed || !strings.Contains(result.Reason, "test") {
+		t.Errorf("result = %+v, want checks-failed mentioning test", result)
+	}
+	if log.has("PUT /repos/o/r/pulls/7/merge") {
+		t.Error("PR should not be merged when checks fail")
+	}
+}
+
+func TestMergeReleasePR_FallbackTimesOut(t *testing.T) {
+	t.Parallel()
+
+	log := &requestLog{}
+	mux := mergeTestServer(t, log, `{"errors":[{"message":"Auto merge is not allowed for this repository"}]}`)
+	mux.HandleFunc("/repos/o/r/branches/main/protection/required_status_checks", func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, `{"message":"Branch not protected"}`, http.StatusNotFound)
+	})
+	mux.HandleFunc("/repos/o/r/commits/abc/status", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"statuses":[]}`))
+	})
+	mux.HandleFunc("/repos/o/r/commits/abc/check-runs", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"check_runs":[]}`))
+	})
+	client := newTestClient(t, mux)
+
+	result, err := client.MergeReleasePR(context.Background(), MergeRequest{
+		Owner: "o", Repo: "r", Number: 7, Method: MergeMethodSquash,
+		Timeout: 50 * time.Millisecond, PollInterval: 5 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("MergeReleasePR() unexpected error: %v", err)
+	}
+	if result.Status != MergeStatusTimedOut {
+		t.Errorf("Status = %q, want %q", result.Status, MergeStatusTimedOut)
+	}
+}
@@ -0,0 +1,177 @@
+// Copyright 2025 Stacklok, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+
+	"github.com/google/go-github/v60/github"
+)
+
+// DefaultLabelColor is the colour used when creating missing labels.
+const DefaultLabelColor = "ededed"
+
+// labelColorPattern matches a six digit hex colour without the leading '#'.
+var labelColorPattern = regexp.MustCompile(`^[0-9a-fA-F]{6}$`)
+
+// ValidateLabelColor checks that color is a six digit hex colour, as required by the GitHub API.
+func ValidateLabelColor(color string) error {
+	if !labelColorPattern.MatchString(color) {
+		return fmt.Errorf("invalid label color %q (expected six hex digits, e.g. %q)", color, DefaultLabelColor)
+	}
+	return nil
+}
+
+// applyPRMetadata sets labels, reviewers, assignees and the milestone on a newly created PR.
+// Failures do not abort the release: the PR already exists at this point, so each
+// failure is returned as a warning for the caller to surface.
+func (c *Client) applyPRMetadata(ctx context.Context, req PRRequest, number int) []string {
+	var warnings []string
+	warn := func(format string, args ...any) {
+		warnings = append(warnings, fmt.Sprintf(format, args...))
+	}
+
+	if len(req.Labels) > 0 {
+		if err := c.addLabels(ctx, req, number); err != nil {
+			warn("adding labels %v to PR #%d: %v", req.Labels, number, err)
+		}
+	}
+
+	if len(req.Reviewers) > 0 || len(req.TeamReviewers) > 0 {
+		_, _, err := c.client.PullRequests.RequestReviewers(ctx, req.Owner, req.Repo, number, github.ReviewersRequest{
+			Reviewers:     req.Reviewers,
+			TeamReviewers: req.TeamReviewers,
+		})
+		if err != nil {
+			warn("requesting reviewers on PR #%d: %v", number, err)
+		}
+	}
+
+	if len(req.Assignees) > 0 {
+		if _, _, err := c.client.Issues.AddAssignees(ctx, req.Owner, req.Repo, number, req.Assignees); err != nil {
+			warn("assigning %v to PR #%d: %v", req.Assignees, number, err)
+		}
+	}
+
+	if req.Milestone != "" {
+		if err := c.setMilestone(ctx, req, number); err != nil {
+			warn("setting milestone %q on PR #%d: %v", req.Milestone, number, err)
+		}
+	}
+
+	return warnings
+}
+
+// addLabels creates any missing labels and adds them to the PR. A label that cannot be
+// looked up or created does not stop the others from being added; all failures are
+// returned together.
+func (c *Client) addLabels(ctx context.Context, req PRRequest, number int) error {
+	color := req.LabelColor
+	if color == "" {
+		color = DefaultLabelColor
+	}
+
+	var errs []error
+	labels := make([]string, 0, len(req.Labels))
+	for _, name := range req.Labels {
+		if err := c.ensureLabel(ctx, req.Owner, req.Repo, name, color); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		labels = append(labels, name)
+	}
+
+	if len(labels) > 0 {
+		if _, _, err := c.client.Issues.AddLabelsToIssue(ctx, req.Owner, req.Repo, number, labels); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ensureLabel creates the label with the given colour if it does not exist.
+func (c *Client) ensureLabel(ctx context.Context, owner, repo, name, color string) error {
+	_, _, err := c.client.Issues.GetLabel(ctx, owner, repo, name)
+	if err == nil {
+		return nil
+	}
+	if !isNotFound(err) {
+		return fmt.Errorf("getting label %q: %w", name, err)
+	}
+	_, _, err = c.client.Issues.CreateLabel(ctx, owner, repo, &github.Label{
+		Name:  github.String(name),
+		Color: github.String(color),
+	})
+	if err != nil {
+		return fmt.Errorf("creating label %q: %w", name, err)
+	}
+	return nil
+}
+
+// setMilestone attaches the milestone with the requested title to the PR, creating it if absent.
+func (c *Client) setMilestone(ctx context.Context, req PRRequest, number int) error {
+	milestoneNumber, err := c.findMilestone(ctx, req.Owner, req.Repo, req.Milestone)
+	if err != nil {
+		return err
+	}
+
+	if milestoneNumber == 0 {
+		m, _, err := c.client.Issues.CreateMilestone(ctx, req.Owner, req.Repo, &github.Milestone{
+			Title: github.String(req.Milestone),
+		})
+		if err != nil {
+			return fmt.Errorf("creating milestone: %w", err)
+		}
+		milestoneNumber = m.GetNumber()
+	}
+
+	_, _, err = c.client.Issues.Edit(ctx, req.Owner, req.Repo, number, &github.IssueRequest{
+		Milestone: github.Int(milestoneNumber),
+	})
+	return err
+}
+
+// findMilestone returns the number of the milestone with the given title, or 0 if none exists.
+func (c *Client) findMilestone(ctx context.Context, owner, repo, title string) (int, error) {
+	opts := &github.MilestoneListOptions{
+		State:       "all",
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+	for {
+		milestones, resp, err := c.client.Issues.ListMilestones(ctx, owner, repo, opts)
+		if err != nil {
+			return 0, fmt.Errorf("listing milestones: %w", err)
+		}
+		for _, m := range milestones {
+			if m.GetTitle() == title {
+				return m.GetNumber(), nil
+			}
+		}
+		if resp.NextPage == 0 {
+			return 0, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+// isNotFound reports whether err is a GitHub API 404 response.
+func isNotFound(err error) bool {
+	var errResp *github.ErrorResponse
+	return errors.As(err, &errResp) && errResp.Response != nil && errResp.Response.StatusCode == http.StatusNotFound
+}
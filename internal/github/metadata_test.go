@@ -0,0 +1,257 @@
+// Copyright 2025 Stacklok, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/go-github/v60/github"
+)
+
+// newTestClient returns a Client talking to an httptest server serving mux.
+func newTestClient(t *testing.T, mux *http.ServeMux) *Client {
+	t.Helper()
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	gh := github.NewClient(nil)
+	baseURL, err := url.Parse(server.URL + "/")
+	if err != nil {
+		t.Fatalf("parsing server URL: %v", err)
+	}
+	gh.BaseURL = baseURL
+	gh.UploadURL = baseURL
+
+	return &Client{client: gh, fileReader: &osFileReader{}}
+}
+
+// requestLog records the API calls made against a test server.
+type requestLog struct {
+	mu    sync.Mutex
+	calls []string
+	// bodies holds the decoded JSON body of each call, keyed by "METHOD path".
+	bodies map[string]map[string]any
+}
+
+func (l *requestLog) record(t *testing.T, r *http.Request) {
+	t.Helper()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	key := r.Method + " " + r.URL.Path
+	l.calls = append(l.calls, key)
+	var body map[string]any
+	if err := json.NewDecoder(r.Body).Decode(&body); err == nil {
+		if l.bodies == nil {
+			l.bodies = map[string]map[string]any{}
+		}
+		l.bodies[key] = body
+	}
+}
+
+func (l *requestLog) has(call string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, c := range l.calls {
+		if c == call {
+			return true
+		}
+	}
+	return false
+}
+
+func TestValidateLabelColor(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		color   string
+		wantErr bool
+	}{
+		{color: "ededed"},
+		{color: "B60205"},
+		{color: "#ededed", wantErr: true},
+		{color: "red", wantErr: true},
+		{color: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.color, func(t *testing.T) {
+			t.Parallel()
+			if err := ValidateLabelColor(tt.color); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateLabelColor(%q) error = %v, wantErr %v", tt.color, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestApplyPRMetadata(t *testing.T) {
+	t.Parallel()
+
+	log := &requestLog{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/o/r/labels/release", func(w http.ResponseWriter, r *http.Request) {
+		log.record(t, r)
+		_, _ = w.Write([]byte(`{"name":"release"}`))
+	})
+	mux.HandleFunc("/repos/o/r/labels/needs-qa", func(w http.ResponseWriter, r *http.Request) {
+		log.record(t, r)
+		http.Error(w, `{"message":"Not Found"}`, http.StatusNotFound)
+	})
+	mux.HandleFunc("/repos/o/r/labels", func(w http.ResponseWriter, r *http.Request) {
+		log.record(t, r)
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"name":"needs-qa"}`))
+	})
+	mux.HandleFunc("/repos/o/r/issues/7/labels", func(w http.ResponseWriter, r *http.Request) {
+		log.record(t, r)
+		_, _ = w.Write([]byte(`[]`))
+	})
+	mux.HandleFunc("/repos/o/r/pulls/7/requested_reviewers", func(w http.ResponseWriter, r *http.Request) {
+		log.record(t, r)
+		http.Error(w, `{"message":"Reviews may only be requested from collaborators"}`, http.StatusUnprocessableEntity)
+	})
+	mux.HandleFunc("/repos/o/r/issues/7/assignees", func(w http.ResponseWriter, r *http.Request) {
+		log.record(t, r)
+		_, _ = w.Write([]byte(`{"number":7}`))
+	})
+	mux.HandleFunc("/repos/o/r/milestones", func(w http.ResponseWriter, r *http.Request) {
+		log.record(t, r)
+		if r.Method == http.MethodGet {
+			_, _ = w.Write([]byte(`[{"number":1,"title":"v1.0.0"}]`))
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"number":2,"title":"v1.1.0"}`))
+	})
+	mux.HandleFunc("/repos/o/r/issues/7", func(w http.ResponseWriter, r *http.Request) {
+		log.record(t, r)
+		_, _ = w.Write([]byte(`{"number":7}`))
+	})
+
+	client := newTestClient(t, mux)
+	warnings := client.applyPRMetadata(context.Background(), PRRequest{
+		Owner:      "o",
+		Repo:       "r",
+		Labels:     []string{"release", "needs-qa"},
+		LabelColor: "b60205",
+		Reviewers:  []string{"outsider"},
+		Assignees:  []string{"octocat"},
+		Milestone:  "v1.1.0",
+	}, 7)
+
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "requesting reviewers") {
+		t.Errorf("applyPRMetadata() warnings = %v, want a single reviewer warning", warnings)
+	}
+
+	for _, call := range []string{
+		"POST /repos/o/r/labels",
+		"POST /repos/o/r/issues/7/labels",
+		"POST /repos/o/r/issues/7/assignees",
+		"POST /repos/o/r/milestones",
+		"PATCH /repos/o/r/issues/7",
+	} {
+		if !log.has(call) {
+			t.Errorf("expected API call %q, got %v", call, log.calls)
+		}
+	}
+
+	if got := log.bodies["POST /repos/o/r/labels"]["color"]; got != "b60205" {
+		t.Errorf("created label color = %v, want %q", got, "b60205")
+	}
+	if got := log.bodies["PATCH /repos/o/r/issues/7"]["milestone"]; got != float64(2) {
+		t.Errorf("milestone = %v, want 2", got)
+	}
+}
+
+func TestApplyPRMetadata_ExistingMilestone(t *testing.T) {
+	t.Parallel()
+
+	log := &requestLog{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/o/r/milestones", func(w http.ResponseWriter, r *http.Request) {
+		log.record(t, r)
+		_, _ = w.Write([]byte(`[{"number":3,"title":"v1.1.0"}]`))
+	})
+	mux.HandleFunc("/repos/o/r/issues/7", func(w http.ResponseWriter, r *http.Request) {
+		log.record(t, r)
+		_, _ = w.Write([]byte(`{"number":7}`))
+	})
+
+	client := newTestClient(t, mux)
+	warnings := client.applyPRMetadata(context.Background(), PRRequest{Owner: "o", Repo: "r", Milestone: "v1.1.0"}, 7)
+	if len(warnings) != 0 {
+		t.Errorf("applyPRMetadata() warnings = %v, want none", warnings)
+	}
+	if log.has("POST /repos/o/r/milestones") {
+		t.Error("existing milestone should not be recreated")
+	}
+	if got := log.bodies["PATCH /repos/o/r/issues/7"]["milestone"]; got != float64(3) {
+		t.Errorf("milestone = %v, want 3", got)
+	}
+}
+
+func TestApplyPRMetadata_LabelFailureIsWarning(t *testing.T) {
+	t.Parallel()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/o/r/labels/release", func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, `{"message":"Resource not accessible by integration"}`, http.StatusForbidden)
+	})
+
+	client := newTestClient(t, mux)
+	warnings := client.applyPRMetadata(context.Background(), PRRequest{Owner: "o", Repo: "r", Labels: []string{"release"}}, 7)
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "adding labels") {
+		t.Errorf("applyPRMetadata() warnings = %v, want a single label warning", warnings)
+	}
+}
+
+func TestApplyPRMetadata_LabelFailureAddsOtherLabels(t *testing.T) {
+	t.Parallel()
+
+	log := &requestLog{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/o/r/labels/release", func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, `{"message":"Resource not accessible by integration"}`, http.StatusForbidden)
+	})
+	mux.HandleFunc("/repos/o/r/labels/needs-qa", func(w http.ResponseWriter, r *http.Request) {
+		log.record(t, r)
+		_, _ = w.Write([]byte(`{"name":"needs-qa"}`))
+	})
+	mux.HandleFunc("/repos/o/r/labels/blocked", func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, `{"message":"Server Error"}`, http.StatusInternalServerError)
+	})
+	mux.HandleFunc("/repos/o/r/issues/7/labels", func(w http.ResponseWriter, r *http.Request) {
+		log.record(t, r)
+		_, _ = w.Write([]byte(`[]`))
+	})
+
+	client := newTestClient(t, mux)
+	warnings := client.applyPRMetadata(context.Background(), PRRequest{
+		Owner: "o", Repo: "r", Labels: []string{"release", "needs-qa", "blocked"},
+	}, 7)
+
+	if len(warnings) != 1 || !strings.Contains(warnings[0], `"release"`) || !strings.Contains(warnings[0], `"blocked"`) {
+		t.Errorf("applyPRMetadata() warnings = %v, want one warning naming both failed labels", warnings)
+	}
+	if !log.has("POST /repos/o/r/issues/7/labels") {
+		t.Fatalf("labels were not added to the PR, calls: %v", log.calls)
+	}
+}
@@ -30,7 +30,20 @@ func (c *Client) CreateReleasePR(ctx context.Context, req PRRequest) (*PRResult,
 	// Get the base branch reference
 	baseRef, _, err := c.client.Git.GetRef(ctx, req.Owner, req.Repo, "refs/heads/"+req.BaseBranch)
 	if err != nil {
-		return nil, fmt.Errorf("getting base branch ref: %w", err)
+		return nil, fmt.Errorf("getting base branch ref from %s: %w", c.Host(), err)
+	}
+
+	// Deduplicate files - each file already has all YAML path changes applied
+	// on disk, so committing the same file twice causes 409 conflicts due to
+	// GitHub API eventual consistency with sequential SHA updates.
+	uniqueFiles := deduplicateFiles(req.Files)
+
+	// Work out the changes before creating the branch, so that a release without
+	// changes fails without leaving a branch behind
+	baseCommit, entries, err := c.planCommit(ctx, req.Owner, req.Repo, baseRef.GetObject().GetSHA(),
+		req.BaseBranch, uniqueFiles, req.DeletedFiles)
+	if err != nil {
+		return nil, fmt.Errorf("committing files: %w", err)
 	}
 
 	// Create the new branch
@@ -44,13 +57,9 @@ func (c *Client) CreateReleasePR(ctx context.Context, req PRRequest) (*PRResult,
 		return nil, fmt.Errorf("creating branch: %w", err)
 	}
 
-	// Deduplicate files - each file already has all YAML path changes applied
-	// on disk, so committing the same file twice causes 409 conflicts due to
-	// GitHub API eventual consistency with sequential SHA updates.
-	uniqueFiles := deduplicateFiles(req.Files)
-
 	// Commit all files to the new branch in a single atomic commit
-	if err := c.commitFiles(ctx, req.Owner, req.Repo, req.HeadBranch, uniqueFiles, req.TriggeredBy); err != nil {
+	message := buildCommitMessage(req.CommitMessage, req.TriggeredBy)
+	if err := c.commitTree(ctx, req.Owner, req.Repo, req.HeadBranch, baseCommit, entries, message); err != nil {
 		return nil, fmt.Errorf("committing files: %w", err)
 	}
 
@@ -60,17 +69,24 @@ func (c *Client) CreateReleasePR(ctx context.Context, req PRRequest) (*PRResult,
 		Head:  github.String(req.HeadBranch),
 		Base:  github.String(req.BaseBranch),
 		Body:  github.String(req.Body),
+		Draft: github.Bool(req.Draft),
 	})
 	if err != nil {
-		return nil, fmt.Errorf("creating pull request: %w", err)
+		return nil, fmt.Errorf("creating pull request on %s: %w", c.Host(), err)
 	}
 
-	// Add release label (non-fatal if it fails, label might not exist)
-	_, _, _ = c.client.Issues.AddLabelsToIssue(ctx, req.Owner, req.Repo, pr.GetNumber(), []string{"release"})
+	// Labels, reviewers, assignees and milestone are non-fatal: the PR exists already
+	warnings := c.applyPRMetadata(ctx, req, pr.GetNumber())
+
+	prURL := pr.GetHTMLURL()
+	if prURL == "" {
+		prURL = c.pullRequestURL(req.Owner, req.Repo, pr.GetNumber())
+	}
 
 	return &PRResult{
-		Number: pr.GetNumber(),
-		URL:    pr.GetHTMLURL(),
+		Number:   pr.GetNumber(),
+		URL:      prURL,
+		Warnings: warnings,
 	}, nil
 }
 
@@ -88,49 +104,58 @@ func deduplicateFiles(files []string) []string {
 	return unique
 }
 
-// commitFiles commits all files to a branch in a single atomic commit using the Git Data API.
+// buildCommitMessage returns the commit message, falling back to DefaultCommitMessage.
 // If triggeredBy is non-empty, a git trailer is added to the commit message.
-func (c *Client) commitFiles(ctx context.Context, owner, repo, branch string, files []string, triggeredBy string) error {
-	// Get the current branch reference
-	ref, _, err := c.client.Git.GetRef(ctx, owner, repo, "refs/heads/"+branch)
-	if err != nil {
-		return fmt.Errorf("getting branch ref: %w", err)
+func buildCommitMessage(message, triggeredBy string) string {
+	if message == "" {
+		message = DefaultCommitMessage
+	}
+	if triggeredBy != "" {
+		message += fmt.Sprintf("\n\nRelease-Triggered-By: %s", triggeredBy)
 	}
+	return message
+}
 
+// planCommit returns the commit at baseSHA, on branch, and the tree entries that commit
+// all files, and the deletion of all deleted files, on top of it. It fails if every
+// file is identical to the base.
+func (c *Client) planCommit(
+	ctx context.Context,
+	owner, repo, baseSHA, branch string,
+	files, deleted []string,
+) (*github.Commit, []*github.TreeEntry, error) {
 	// Get the commit to find the base tree
-	baseCommit, _, err := c.client.Git.GetCommit(ctx, owner, repo, ref.GetObject().GetSHA())
+	baseCommit, _, err := c.client.Git.GetCommit(ctx, owner, repo, baseSHA)
 	if err != nil {
-		return fmt.Errorf("getting base commit: %w", err)
+		return nil, nil, fmt.Errorf("getting base commit: %w", err)
 	}
 
 	// Build tree entries for all files
-	entries := make([]*github.TreeEntry, 0, len(files))
-	for _, filePath := range files {
-		content, err := c.fileReader.ReadFile(filePath)
-		if err != nil {
-			return fmt.Errorf("reading file %s: %w", filePath, err)
-		}
-		contentStr := string(content)
-		entries = append(entries, &github.TreeEntry{
-			Path:    github.String(filePath),
-			Mode:    github.String("100644"),
-			Type:    github.String("blob"),
-			Content: github.String(contentStr),
-		})
+	entries, err := c.treeEntries(ctx, owner, repo, baseCommit.GetTree().GetSHA(), files, deleted)
+	if err != nil {
+		return nil, nil, err
 	}
+	if len(entries) == 0 {
+		return nil, nil, fmt.Errorf("no changes to commit: all files are identical to %s", branch)
+	}
+	return baseCommit, entries, nil
+}
 
+// commitTree commits the tree entries on top of baseCommit to a branch in a single
+// atomic commit using the Git Data API.
+func (c *Client) commitTree(
+	ctx context.Context,
+	owner, repo, branch string,
+	baseCommit *github.Commit,
+	entries []*github.TreeEntry,
+	message string,
+) error {
 	// Create a new tree with all file changes
 	tree, _, err := c.client.Git.CreateTree(ctx, owner, repo, baseCommit.GetTree().GetSHA(), entries)
 	if err != nil {
 		return fmt.Errorf("creating tree: %w", err)
 	}
 
-	// Build commit message
-	message := "Update release files"
-	if triggeredBy != "" {
-		message += fmt.Sprintf("\n\nRelease-Triggered-By: %s", triggeredBy)
-	}
-
 	// Create the commit
 	commit, _, err := c.client.Git.CreateCommit(ctx, owner, repo,
 		&github.Commit{
@@ -145,7 +170,10 @@ func (c *Client) commitFiles(ctx context.Context, owner, repo, branch string, fi
 	}
 
 	// Update the branch reference to point to the new commit
-	ref.Object.SHA = commit.SHA
+	ref := &github.Reference{
+		Ref:    github.String("refs/heads/" + branch),
+		Object: &github.GitObject{SHA: commit.SHA},
+	}
 	_, _, err = c.client.Git.UpdateRef(ctx, owner, repo, ref, false)
 	if err != nil {
 		return fmt.Errorf("updating ref: %w", err)
@@ -0,0 +1,276 @@
+// Copyright 2025 Stacklok, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package github
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Retry defaults, used for zero fields of RetryConfig.
+const (
+	DefaultMaxAttempts   = 5
+	DefaultRetryDeadline = 5 * time.Minute
+	defaultRetryBase     = time.Second
+	defaultRetryMax      = time.Minute
+)
+
+// RetryConfig controls how failed GitHub API calls are retried.
+// Zero fields select the defaults.
+type RetryConfig struct {
+	// MaxAttempts is the total number of attempts per API call, including the first. 1 disables retries.
+	MaxAttempts int
+	// Deadline bounds the time spent retrying, over all API calls of the run: the time
+	// from the first failed attempt of each retried call until it returns counts against
+	// it. A retry that would have to wait past the deadline is not attempted and the last
+	// failure is returned.
+	Deadline time.Duration
+}
+
+// Validate checks that the retry settings are not negative.
+func (c RetryConfig) Validate() error {
+	if c.MaxAttempts < 0 {
+		return fmt.Errorf("max attempts must not be negative")
+	}
+	if c.Deadline < 0 {
+		return fmt.Errorf("deadline must not be negative")
+	}
+	return nil
+}
+
+// WithRetry configures retries of failed API calls. Without this option the defaults apply.
+func WithRetry(cfg RetryConfig) ClientOption {
+	return func(c *Client) {
+		c.retry = cfg
+	}
+}
+
+// retryTransport retries requests that failed with a server error or hit a rate limit.
+// Waits honour the Retry-After and X-RateLimit-Reset headers and otherwise back off
+// exponentially with jitter.
+type retryTransport struct {
+	base        http.RoundTripper
+	maxAttempts int
+	deadline    time.Duration
+
+	mu sync.Mutex
+	// spent is the time already spent in retried calls, counted against deadline.
+	spent time.Duration
+
+	// Overridable for tests.
+	now   func() time.Time
+	sleep func(ctx context.Context, d time.Duration) error
+	logf  func(format string, args ...any)
+}
+
+// newRetryTransport wraps base with the retry behaviour described by cfg.
+func newRetryTransport(base http.RoundTripper, cfg RetryConfig) *retryTransport {
+	t := &retryTransport{
+		base:        base,
+		maxAttempts: cfg.MaxAttempts,
+		deadline:    cfg.Deadline,
+		now:         time.Now,
+		sleep:       sleepContext,
+		logf: func(format string, args ...any) {
+			fmt.Printf(format+"\n", args...)
+		},
+	}
+	if t.maxAttempts == 0 {
+		t.maxAttempts = DefaultMaxAttempts
+	}
+	if t.deadline == 0 {
+		t.deadline = DefaultRetryDeadline
+	}
+	return t
+}
+
+// RoundTrip sends the request, retrying it while the response is retryable,
+// attempts remain and the wait fits within what is left of the deadline.
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := t.now()
+	retried := false
+	defer func() {
+		if retried {
+			t.spend(t.now().Sub(start))
+		}
+	}()
+
+	for attempt := 1; ; attempt++ {
+		resp, err := t.base.RoundTrip(req)
+		if attempt >= t.maxAttempts || !retryable(req.Method, resp, err) {
+			return resp, err
+		}
+
+		wait := t.retryDelay(resp, attempt)
+		if t.spentTime()+t.now().Add(wait).Sub(start) > t.deadline {
+			return resp, err
+		}
+		if req.Body != nil && req.GetBody == nil {
+			// The body has been consumed and cannot be replayed.
+			return resp, err
+		}
+
+		reason := describeFailure(resp, err)
+		if resp != nil {
+			// Drain so the connection can be reused.
+			_, _ = io.Copy(io.Discard, resp.Body)
+			_ = resp.Body.Close()
+		}
+
+		retried = true
+		t.logf("GitHub API %s %s failed (%s), retrying in %s (attempt %d/%d)",
+			req.Method, req.URL.Path, reason, wait.Round(time.Millisecond), attempt+1, t.maxAttempts)
+		if err := t.sleep(req.Context(), wait); err != nil {
+			return nil, err
+		}
+
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, fmt.Errorf("rewinding request body: %w", err)
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+	}
+}
+
+// spentTime returns the time already spent in retried calls.
+func (t *retryTransport) spentTime() time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.spent
+}
+
+// spend adds d to the time spent in retried calls.
+func (t *retryTransport) spend(d time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.spent += d
+}
+
+// retryable reports whether a request with the given method that produced resp and err
+// should be retried: transport errors, server errors, and primary or secondary rate
+// limits. Server errors, and transport errors once the request may have been sent, are
+// only retried for idempotent methods, since a POST or PATCH behind a 502 or a reset
+// connection may have succeeded, and repeating it would fail with "already exists".
+func retryable(method string, resp *http.Response, err error) bool {
+	if err != nil {
+		// Cancellation comes from the caller and a bad certificate will not fix itself,
+		// so retrying cannot help; other transport errors are usually transient.
+		var certErr *tls.CertificateVerificationError
+		if resp != nil || isContextError(err) || errors.As(err, &certErr) {
+			return false
+		}
+		return idempotent(method) || failedToConnect(err)
+	}
+
+	switch {
+	case resp.StatusCode >= http.StatusInternalServerError && resp.StatusCode != http.StatusNotImplemented:
+		return idempotent(method)
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return true
+	case resp.StatusCode == http.StatusForbidden:
+		// A 403 is only a rate limit if GitHub says so; otherwise it is a permission error.
+		return resp.Header.Get("Retry-After") != "" || resp.Header.Get("X-RateLimit-Remaining") == "0"
+	default:
+		return false
+	}
+}
+
+// failedToConnect reports whether a transport error happened while connecting, before
+// any of the request was sent.
+func failedToConnect(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// idempotent reports whether repeating a request with the given method has the same
+// effect as sending it once.
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// retryDelay returns how long to wait before the next attempt, preferring the server's hints.
+func (t *retryTransport) retryDelay(resp *http.Response, attempt int) time.Duration {
+	if resp != nil {
+		if d, ok := parseRetryAfter(resp.Header.Get("Retry-After"), t.now()); ok {
+			return d
+		}
+		if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+			if reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+				// Allow a second for clock skew between the runner and GitHub.
+				return max(time.Unix(reset, 0).Sub(t.now())+time.Second, 0)
+			}
+		}
+	}
+
+	backoff := min(defaultRetryBase<<(attempt-1), defaultRetryMax)
+	// Jitter avoids retrying in lockstep with other clients hitting the same limit.
+	return backoff/2 + rand.N(backoff/2+1) //nolint:gosec // jitter does not need a secure source
+}
+
+// parseRetryAfter parses a Retry-After header given either in seconds or as an HTTP date.
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return max(time.Duration(seconds)*time.Second, 0), true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return max(at.Sub(now), 0), true
+	}
+	return 0, false
+}
+
+// describeFailure returns a short description of a failed attempt for the retry log.
+func describeFailure(resp *http.Response, err error) string {
+	if err != nil {
+		return err.Error()
+	}
+	return resp.Status
+}
+
+// isContextError reports whether err was caused by a cancelled or expired context.
+func isContextError(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// sleepContext waits for d or until ctx is done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,355 @@
+// Copyright 2025 Stacklok, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package github
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// roundTripFunc adapts a function to http.RoundTripper.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// stubResponse describes one response returned by the stub transport.
+type stubResponse struct {
+	status  int
+	headers map[string]string
+	err     error
+}
+
+func TestRetryTransport(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(1700000000, 0)
+	reset := strconv.FormatInt(now.Add(time.Minute).Unix(), 10)
+
+	tests := []struct {
+		name         string
+		cfg          RetryConfig
+		method       string // defaults to POST
+		responses    []stubResponse
+		wantAttempts int
+		wantStatus   int
+		wantErr      bool
+		wantWaits    []time.Duration
+	}{
+		{
+			name:         "success is not retried",
+			responses:    []stubResponse{{status: 200}},
+			wantAttempts: 1,
+			wantStatus:   200,
+		},
+		{
+			name:         "bad gateway then success",
+			method:       http.MethodGet,
+			responses:    []stubResponse{{status: 502, headers: map[string]string{"Retry-After": "2"}}, {status: 200}},
+			wantAttempts: 2,
+			wantStatus:   200,
+			wantWaits:    []time.Duration{2 * time.Second},
+		},
+		{
+			name:         "permission error is not retried",
+			responses:    []stubResponse{{status: 403}},
+			wantAttempts: 1,
+			wantStatus:   403,
+		},
+		{
+			name:         "not found is not retried",
+			responses:    []stubResponse{{status: 404}},
+			wantAttempts: 1,
+			wantStatus:   404,
+		},
+		{
+			name: "secondary rate limit honours Retry-After",
+			responses: []stubResponse{
+				{status: 403, headers: map[string]string{"Retry-After": "30"}},
+				{status: 201},
+			},
+			wantAttempts: 2,
+			wantStatus:   201,
+			wantWaits:    []time.Duration{30 * time.Second},
+		},
+		{
+			name: "primary rate limit waits for reset",
+			responses: []stubResponse{
+				{status: 429, headers: map[string]string{"X-RateLimit-Remaining": "0", "X-RateLimit-Reset": reset}},
+				{status: 200},
+			},
+			wantAttempts: 2,
+			wantStatus:   200,
+			wantWaits:    []time.Duration{61 * time.Second},
+		},
+		{
+			name:   "gives up after max attempts",
+			cfg:    RetryConfig{MaxAttempts: 3},
+			method: http.MethodPut,
+			responses: []stubResponse{
+				{status: 503, headers: map[string]string{"Retry-After": "1"}},
+				{status: 503, headers: map[string]string{"Retry-After": "1"}},
+				{status: 503, headers: map[string]string{"Retry-After": "1"}},
+			},
+			wantAttempts: 3,
+			wantStatus:   503,
+			wantWaits:    []time.Duration{time.Second, time.Second},
+		},
+		{
+			name:         "wait beyond deadline is not attempted",
+			cfg:          RetryConfig{Deadline: time.Minute},
+			responses:    []stubResponse{{status: 403, headers: map[string]string{"Retry-After": "120"}}},
+			wantAttempts: 1,
+			wantStatus:   403,
+		},
+		{
+			name:         "server error on POST is not retried",
+			responses:    []stubResponse{{status: 502, headers: map[string]string{"Retry-After": "2"}}},
+			wantAttempts: 1,
+			wantStatus:   502,
+		},
+		{
+			name:         "server error on PATCH is not retried",
+			method:       http.MethodPatch,
+			responses:    []stubResponse{{status: 500}},
+			wantAttempts: 1,
+			wantStatus:   500,
+		},
+		{
+			name:         "transport error is retried",
+			method:       http.MethodGet,
+			responses:    []stubResponse{{err: errors.New("connection reset by peer")}, {status: 200}},
+			wantAttempts: 2,
+			wantStatus:   200,
+		},
+		{
+			name:         "transport error on POST is not retried",
+			responses:    []stubResponse{{err: errors.New("connection reset by peer")}},
+			wantAttempts: 1,
+			wantErr:      true,
+		},
+		{
+			name: "connection failure on POST is retried",
+			responses: []stubResponse{
+				{err: &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}},
+				{status: 201},
+			},
+			wantAttempts: 2,
+			wantStatus:   201,
+		},
+		{
+			name:         "cancellation is not retried",
+			responses:    []stubResponse{{err: context.Canceled}},
+			wantAttempts: 1,
+			wantErr:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var bodies []string
+			base := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				data, _ := io.ReadAll(req.Body)
+				bodies = append(bodies, string(data))
+				stub := tt.responses[len(bodies)-1]
+				if stub.err != nil {
+					return nil, stub.err
+				}
+				resp := &http.Response{
+					StatusCode: stub.status,
+					Status:     fmt.Sprintf("%d %s", stub.status, http.StatusText(stub.status)),
+					Header:     http.Header{},
+					Body:       io.NopCloser(strings.NewReader("{}")),
+				}
+				for k, v := range stub.headers {
+					resp.Header.Set(k, v)
+				}
+				return resp, nil
+			})
+
+			var waits []time.Duration
+			rt := newRetryTransport(base, tt.cfg)
+			rt.now = func() time.Time { return now }
+			rt.sleep = func(_ context.Context, d time.Duration) error {
+				waits = append(waits, d)
+				return nil
+			}
+			rt.logf = func(string, ...any) {}
+
+			method := tt.method
+			if method == "" {
+				method = http.MethodPost
+			}
+			req, err := http.NewRequest(method, "https://api.github.com/repos/o/r/git/refs", bytes.NewBufferString(`{"ref":"x"}`))
+			if err != nil {
+				t.Fatalf("NewRequest() error = %v", err)
+			}
+
+			resp, err := rt.RoundTrip(req)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("RoundTrip() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if resp != nil {
+				defer resp.Body.Close()
+				if resp.StatusCode != tt.wantStatus {
+					t.Errorf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+				}
+			}
+			if len(bodies) != tt.wantAttempts {
+				t.Errorf("attempts = %d, want %d", len(bodies), tt.wantAttempts)
+			}
+			for i, body := range bodies {
+				if body != `{"ref":"x"}` {
+					t.Errorf("attempt %d body = %q, want the original body", i+1, body)
+				}
+			}
+			if tt.wantWaits != nil && fmt.Sprint(waits) != fmt.Sprint(tt.wantWaits) {
+				t.Errorf("waits = %v, want %v", waits, tt.wantWaits)
+			}
+		})
+	}
+}
+
+func TestRetryTransport_DeadlineCoversAllCalls(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(1700000000, 0)
+	attempts := 0
+	base := roundTripFunc(func(*http.Request) (*http.Response, error) {
+		attempts++
+		// Every other attempt fails, so each call succeeds on its first retry
+		status := http.StatusOK
+		if attempts%2 == 1 {
+			status = http.StatusServiceUnavailable
+		}
+		return &http.Response{
+			StatusCode: status,
+			Status:     http.StatusText(status),
+			Header:     http.Header{"Retry-After": []string{"20"}},
+			Body:       io.NopCloser(strings.NewReader("{}")),
+		}, nil
+	})
+
+	rt := newRetryTransport(base, RetryConfig{Deadline: 45 * time.Second})
+	rt.now = func() time.Time { return now }
+	rt.sleep = func(_ context.Context, d time.Duration) error {
+		now = now.Add(d)
+		return nil
+	}
+	rt.logf = func(string, ...any) {}
+
+	var statuses []int
+	for range 3 {
+		req, err := http.NewRequest(http.MethodGet, "https://api.github.com/repos/o/r", nil)
+		if err != nil {
+			t.Fatalf("NewRequest() error = %v", err)
+		}
+		resp, err := rt.RoundTrip(req)
+		if err != nil {
+			t.Fatalf("RoundTrip() error = %v", err)
+		}
+		resp.Body.Close()
+		statuses = append(statuses, resp.StatusCode)
+	}
+
+	// The first two calls spend 40s of the 45s budget; the third cannot wait another 20s
+	if want := []int{200, 200, 503}; fmt.Sprint(statuses) != fmt.Sprint(want) {
+		t.Errorf("statuses = %v, want %v", statuses, want)
+	}
+}
+
+func TestRetryTransport_BackoffWithoutHints(t *testing.T) {
+	t.Parallel()
+
+	rt := newRetryTransport(http.DefaultTransport, RetryConfig{})
+	for attempt := 1; attempt <= 10; attempt++ {
+		want := min(defaultRetryBase<<(attempt-1), defaultRetryMax)
+		got := rt.retryDelay(&http.Response{Header: http.Header{}}, attempt)
+		if got < want/2 || got > want {
+			t.Errorf("retryDelay(attempt %d) = %s, want within [%s, %s]", attempt, got, want/2, want)
+		}
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		value  string
+		want   time.Duration
+		wantOK bool
+	}{
+		{name: "empty", value: ""},
+		{name: "seconds", value: "42", want: 42 * time.Second, wantOK: true},
+		{name: "http date", value: now.Add(90 * time.Second).Format(http.TimeFormat), want: 90 * time.Second, wantOK: true},
+		{name: "date in the past", value: now.Add(-time.Hour).Format(http.TimeFormat), want: 0, wantOK: true},
+		{name: "garbage", value: "soon"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, ok := parseRetryAfter(tt.value, now)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("parseRetryAfter(%q) = %s, %v, want %s, %v", tt.value, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestNewClient_RetriesServerErrors(t *testing.T) {
+	t.Parallel()
+
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls++
+		if calls == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		fmt.Fprint(w, `{"ref": "refs/heads/main", "object": {"sha": "abc"}}`)
+	}))
+	t.Cleanup(server.Close)
+
+	c, err := NewClient(context.Background(), "token", WithBaseURL(server.URL+"/api/v3/"))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	ref, _, err := c.client.Git.GetRef(context.Background(), "o", "r", "heads/main")
+	if err != nil {
+		t.Fatalf("GetRef() error = %v", err)
+	}
+	if ref.GetObject().GetSHA() != "abc" || calls != 2 {
+		t.Errorf("GetRef() sha = %q after %d calls, want abc after 2", ref.GetObject().GetSHA(), calls)
+	}
+}
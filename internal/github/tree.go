@@ -0,0 +1,193 @@
+// Copyright 2025 Stacklok, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package github
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1" //nolint:gosec // git object IDs are SHA-1
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"unicode/utf8"
+
+	"github.com/google/go-github/v60/github"
+)
+
+// Git tree entry modes.
+const (
+	modeFile       = "100644"
+	modeExecutable = "100755"
+	modeSymlink    = "120000"
+)
+
+// localBlob is the content and mode of a local file to be committed.
+type localBlob struct {
+	path    string
+	mode    string
+	content []byte
+}
+
+// treeEntries builds the tree entries for a commit writing files and deleting deleted
+// on top of the tree baseTreeSHA. A path listed in both is deleted. Files whose content
+// and mode are identical to the base tree, and deletions of paths absent from it, are
+// skipped, so the commit only contains real changes.
+func (c *Client) treeEntries(
+	ctx context.Context,
+	owner, repo, baseTreeSHA string,
+	files, deleted []string,
+) ([]*github.TreeEntry, error) {
+	base, err := c.baseTreeBlobs(ctx, owner, repo, baseTreeSHA)
+	if err != nil {
+		return nil, err
+	}
+
+	isDeleted := make(map[string]bool, len(deleted))
+	for _, path := range deleted {
+		isDeleted[path] = true
+	}
+
+	entries := make([]*github.TreeEntry, 0, len(files)+len(deleted))
+	for _, path := range files {
+		if isDeleted[path] {
+			continue
+		}
+		blob, err := c.readBlob(path)
+		if err != nil {
+			return nil, err
+		}
+		if blob.matches(base[path]) {
+			fmt.Printf("Skipping %s: identical to the base branch\n", path)
+			continue
+		}
+		entry, err := c.blobEntry(ctx, owner, repo, blob)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	for _, path := range deduplicateFiles(deleted) {
+		mode := modeFile
+		if base != nil {
+			existing, ok := base[path]
+			if !ok {
+				continue
+			}
+			mode = existing.GetMode()
+		}
+		// A blob entry without SHA or content is sent as "sha": null, which removes the path
+		entries = append(entries, &github.TreeEntry{
+			Path: github.String(path),
+			Mode: github.String(mode),
+			Type: github.String("blob"),
+		})
+	}
+
+	return entries, nil
+}
+
+// matches reports whether existing is a tree entry with the same mode and content as b.
+func (b localBlob) matches(existing *github.TreeEntry) bool {
+	return existing != nil && existing.GetMode() == b.mode && existing.GetSHA() == gitBlobSHA(b.content)
+}
+
+// baseTreeBlobs returns the blobs of the base tree keyed by path. It returns nil if the
+// tree is too large for GitHub to list in full, in which case nothing is skipped.
+func (c *Client) baseTreeBlobs(ctx context.Context, owner, repo, treeSHA string) (map[string]*github.TreeEntry, error) {
+	tree, _, err := c.client.Git.GetTree(ctx, owner, repo, treeSHA, true)
+	if err != nil {
+		return nil, fmt.Errorf("getting base tree: %w", err)
+	}
+	if tree.GetTruncated() {
+		return nil, nil
+	}
+
+	blobs := make(map[string]*github.TreeEntry, len(tree.Entries))
+	for _, entry := range tree.Entries {
+		if entry.GetType() == "blob" {
+			blobs[entry.GetPath()] = entry
+		}
+	}
+	return blobs, nil
+}
+
+// readBlob reads the local file at path, preserving its executable bit and symlink type.
+func (c *Client) readBlob(path string) (localBlob, error) {
+	mode := modeFile
+	if infoReader, ok := c.fileReader.(FileInfoReader); ok {
+		info, err := infoReader.Lstat(path)
+		if err != nil {
+			return localBlob{}, fmt.Errorf("reading file %s: %w", path, err)
+		}
+
+		switch {
+		case info.Mode()&fs.ModeSymlink != 0:
+			target, err := infoReader.Readlink(path)
+			if err != nil {
+				return localBlob{}, fmt.Errorf("reading symlink %s: %w", path, err)
+			}
+			// The blob of a symlink holds its target path
+			return localBlob{path: path, mode: modeSymlink, content: []byte(target)}, nil
+		case info.Mode()&0o111 != 0:
+			mode = modeExecutable
+		}
+	}
+
+	content, err := c.fileReader.ReadFile(path)
+	if err != nil {
+		return localBlob{}, fmt.Errorf("reading file %s: %w", path, err)
+	}
+	return localBlob{path: path, mode: mode, content: content}, nil
+}
+
+// blobEntry returns a tree entry for blob, with the content inline for text and
+// uploaded through the Blobs API for binary data, since inline content must be valid UTF-8.
+func (c *Client) blobEntry(ctx context.Context, owner, repo string, blob localBlob) (*github.TreeEntry, error) {
+	entry := &github.TreeEntry{
+		Path: github.String(blob.path),
+		Mode: github.String(blob.mode),
+		Type: github.String("blob"),
+	}
+
+	if !isBinary(blob.content) {
+		entry.Content = github.String(string(blob.content))
+		return entry, nil
+	}
+
+	created, _, err := c.client.Git.CreateBlob(ctx, owner, repo, &github.Blob{
+		Content:  github.String(base64.StdEncoding.EncodeToString(blob.content)),
+		Encoding: github.String("base64"),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("creating blob for %s: %w", blob.path, err)
+	}
+	entry.SHA = created.SHA
+	return entry, nil
+}
+
+// gitBlobSHA returns the git object ID of a blob with the given content.
+func gitBlobSHA(content []byte) string {
+	h := sha1.New() //nolint:gosec // git object IDs are SHA-1
+	fmt.Fprintf(h, "blob %d\x00", len(content))
+	h.Write(content)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// isBinary reports whether content cannot be sent inline as a tree entry's content.
+func isBinary(content []byte) bool {
+	return bytes.IndexByte(content, 0) >= 0 || !utf8.Valid(content)
+}
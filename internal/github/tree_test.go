@@ -0,0 +1,281 @@
+// Copyright 2025 Stacklok, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package github
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// mapFileReader serves files from an in-memory file system, including modes and symlinks.
+type mapFileReader struct {
+	fsys fstest.MapFS
+}
+
+func (m *mapFileReader) ReadFile(path string) ([]byte, error) { return m.fsys.ReadFile(path) }
+
+func (m *mapFileReader) Lstat(path string) (fs.FileInfo, error) { return m.fsys.Lstat(path) }
+
+func (m *mapFileReader) Readlink(path string) (string, error) { return m.fsys.ReadLink(path) }
+
+// plainFileReader only implements FileReader, without file modes.
+type plainFileReader struct {
+	fsys fstest.MapFS
+}
+
+func (p *plainFileReader) ReadFile(path string) ([]byte, error) { return p.fsys.ReadFile(path) }
+
+func TestCommitTree_TreeEntries(t *testing.T) {
+	t.Parallel()
+
+	binary := []byte{0x89, 'P', 'N', 'G', 0x00, 0xff}
+	fsys := fstest.MapFS{
+		"VERSION":             {Data: []byte("1.2.3\n"), Mode: 0o644},
+		"scripts/release.sh":  {Data: []byte("#!/bin/sh\necho release\n"), Mode: 0o755},
+		"docs/latest":         {Data: []byte("v1.2.3"), Mode: fs.ModeSymlink | 0o777},
+		"docs/logo.png":       {Data: binary, Mode: 0o644},
+		"charts/app/old.yaml": {Data: []byte("stale"), Mode: 0o644},
+	}
+
+	// The base tree has an unchanged copy of VERSION, the executable script
+	// with the wrong mode, and the files to delete.
+	baseTree := fmt.Sprintf(`{"sha": "basetree", "truncated": false, "tree": [
+		{"path": "VERSION", "mode": "100644", "type": "blob", "sha": %q},
+		{"path": "scripts/release.sh", "mode": "100644", "type": "blob", "sha": %q},
+		{"path": "charts/app/old.yaml", "mode": "100644", "type": "blob", "sha": "1111"},
+		{"path": "charts/app/run.sh", "mode": "100755", "type": "blob", "sha": "2222"},
+		{"path": "charts", "mode": "040000", "type": "tree", "sha": "3333"}
+	]}`, gitBlobSHA([]byte("1.2.3\n")), gitBlobSHA(fsys["scripts/release.sh"].Data))
+
+	tests := []struct {
+		name      string
+		reader    FileReader
+		files     []string
+		deleted   []string
+		baseTree  string
+		wantModes map[string]string
+		wantBlob  bool
+		wantErr   bool
+	}{
+		{
+			name:   "modes, symlinks, binaries and deletions",
+			reader: &mapFileReader{fsys: fsys},
+			files:  []string{"scripts/release.sh", "docs/latest", "docs/logo.png", "charts/app/old.yaml"},
+			// A deleted path is not read, even if it is also listed as modified
+			deleted:  []string{"charts/app/old.yaml", "charts/app/run.sh"},
+			baseTree: `{"sha": "basetree", "tree": [], "truncated": true}`,
+			wantModes: map[string]string{
+				"scripts/release.sh":  modeExecutable,
+				"docs/latest":         modeSymlink,
+				"docs/logo.png":       modeFile,
+				"charts/app/old.yaml": modeFile,
+				"charts/app/run.sh":   modeFile,
+			},
+			wantBlob: true,
+		},
+		{
+			name:    "unchanged files and absent deletions are skipped",
+			reader:  &mapFileReader{fsys: fsys},
+			files:   []string{"VERSION", "scripts/release.sh"},
+			deleted: []string{"charts/app/run.sh", "charts/app/never-existed.yaml"},
+			// The script is only committed because its mode changed
+			wantModes: map[string]string{
+				"scripts/release.sh": modeExecutable,
+				"charts/app/run.sh":  modeExecutable,
+			},
+		},
+		{
+			// Without file info the script is a regular file, like in the base tree
+			name:      "reader without file info and nothing to commit",
+			reader:    &plainFileReader{fsys: fsys},
+			files:     []string{"VERSION", "scripts/release.sh"},
+			wantModes: map[string]string{},
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			log := &requestLog{}
+			mux := http.NewServeMux()
+			mux.HandleFunc("GET /repos/o/r/git/commits/base", func(w http.ResponseWriter, _ *http.Request) {
+				fmt.Fprint(w, `{"sha": "base", "tree": {"sha": "basetree"}}`)
+			})
+			mux.HandleFunc("GET /repos/o/r/git/trees/basetree", func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Query().Get("recursive") == "" {
+					t.Error("base tree requested without recursive=1")
+				}
+				if tt.baseTree != "" {
+					fmt.Fprint(w, tt.baseTree)
+					return
+				}
+				fmt.Fprint(w, baseTree)
+			})
+			mux.HandleFunc("POST /repos/o/r/git/blobs", func(w http.ResponseWriter, r *http.Request) {
+				log.record(t, r)
+				fmt.Fprint(w, `{"sha": "blobsha"}`)
+			})
+			mux.HandleFunc("POST /repos/o/r/git/trees", func(w http.ResponseWriter, r *http.Request) {
+				log.record(t, r)
+				fmt.Fprint(w, `{"sha": "newtree"}`)
+			})
+			mux.HandleFunc("POST /repos/o/r/git/commits", func(w http.ResponseWriter, _ *http.Request) {
+				fmt.Fprint(w, `{"sha": "newcommit"}`)
+			})
+			mux.HandleFunc("PATCH /repos/o/r/git/refs/heads/release", func(w http.ResponseWriter, _ *http.Request) {
+				fmt.Fprint(w, `{"ref": "refs/heads/release", "object": {"sha": "newcommit"}}`)
+			})
+
+			c := newTestClient(t, mux)
+			c.fileReader = tt.reader
+
+			ctx := context.Background()
+			baseCommit, treeEntries, err := c.planCommit(ctx, "o", "r", "base", "main", tt.files, tt.deleted)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("planCommit() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if err := c.commitTree(ctx, "o", "r", "release", baseCommit, treeEntries, "msg"); err != nil {
+				t.Fatalf("commitTree() error = %v", err)
+			}
+
+			tree := log.bodies["POST /repos/o/r/git/trees"]
+			if tree["base_tree"] != "basetree" {
+				t.Errorf("base_tree = %v, want basetree", tree["base_tree"])
+			}
+
+			entries := map[string]map[string]any{}
+			for _, e := range tree["tree"].([]any) {
+				entry := e.(map[string]any)
+				entries[entry["path"].(string)] = entry
+			}
+			if len(entries) != len(tt.wantModes) {
+				t.Errorf("tree has %d entries, want %d: %v", len(entries), len(tt.wantModes), entries)
+			}
+			for path, mode := range tt.wantModes {
+				if entries[path]["mode"] != mode {
+					t.Errorf("%s mode = %v, want %s", path, entries[path]["mode"], mode)
+				}
+			}
+
+			if !tt.wantBlob {
+				if log.has("POST /repos/o/r/git/blobs") {
+					t.Error("unexpected blob upload for text content")
+				}
+				return
+			}
+
+			if got := entries["docs/latest"]["content"]; got != "v1.2.3" {
+				t.Errorf("symlink content = %v, want its target", got)
+			}
+			if got := entries["docs/logo.png"]; got["sha"] != "blobsha" || got["content"] != nil {
+				t.Errorf("binary entry = %v, want blob SHA without inline content", got)
+			}
+			blob := log.bodies["POST /repos/o/r/git/blobs"]
+			if blob["encoding"] != "base64" || blob["content"] != base64.StdEncoding.EncodeToString(binary) {
+				t.Errorf("blob = %v, want base64 encoded binary content", blob)
+			}
+			for _, path := range tt.deleted {
+				sha, ok := entries[path]["sha"]
+				if !ok || sha != nil || entries[path]["content"] != nil {
+					t.Errorf("deleted entry %s = %v, want sha: null", path, entries[path])
+				}
+			}
+		})
+	}
+}
+
+func TestCreateReleasePR_NoChanges(t *testing.T) {
+	t.Parallel()
+
+	log := &requestLog{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /repos/o/r/git/ref/heads/main", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"ref": "refs/heads/main", "object": {"sha": "base"}}`)
+	})
+	mux.HandleFunc("GET /repos/o/r/git/commits/base", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"sha": "base", "tree": {"sha": "basetree"}}`)
+	})
+	mux.HandleFunc("GET /repos/o/r/git/trees/basetree", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprintf(w, `{"sha": "basetree", "truncated": false, "tree": [
+			{"path": "VERSION", "mode": "100644", "type": "blob", "sha": %q}
+		]}`, gitBlobSHA([]byte("1.2.3\n")))
+	})
+	mux.HandleFunc("POST /repos/o/r/git/refs", func(w http.ResponseWriter, r *http.Request) {
+		log.record(t, r)
+		fmt.Fprint(w, `{"ref": "refs/heads/release/v1.2.3", "object": {"sha": "base"}}`)
+	})
+
+	c := newTestClient(t, mux)
+	c.fileReader = &mapFileReader{fsys: fstest.MapFS{"VERSION": {Data: []byte("1.2.3\n"), Mode: 0o644}}}
+
+	_, err := c.CreateReleasePR(context.Background(), PRRequest{
+		Owner: "o", Repo: "r", BaseBranch: "main", HeadBranch: "release/v1.2.3",
+		Title: "Release v1.2.3", Files: []string{"VERSION"},
+	})
+	if err == nil || !strings.Contains(err.Error(), "no changes to commit") {
+		t.Fatalf("CreateReleasePR() error = %v, want no changes error", err)
+	}
+	if log.has("POST /repos/o/r/git/refs") {
+		t.Error("release branch created although there was nothing to commit")
+	}
+}
+
+func TestGitBlobSHA(t *testing.T) {
+	t.Parallel()
+
+	// Object IDs as computed by git hash-object
+	if got := gitBlobSHA(nil); got != "e69de29bb2d1d6434b8b29ae775ad8c2e48c5391" {
+		t.Errorf("gitBlobSHA(empty) = %s", got)
+	}
+	if got := gitBlobSHA([]byte("hello\n")); got != "ce013625030ba8dba906f756967f9e9ca394464a" {
+		t.Errorf("gitBlobSHA(hello) = %s", got)
+	}
+}
+
+func TestIsBinary(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		content []byte
+		want    bool
+	}{
+		{name: "empty", content: nil},
+		{name: "ascii", content: []byte("version: 1.2.3\n")},
+		{name: "utf-8", content: []byte("naïve — ✓\n")},
+		{name: "nul byte", content: []byte("a\x00b"), want: true},
+		{name: "invalid utf-8", content: []byte{0xff, 0xfe, 'a'}, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := isBinary(tt.content); got != tt.want {
+				t.Errorf("isBinary(%q) = %v, want %v", tt.content, got, tt.want)
+			}
+		})
+	}
+}
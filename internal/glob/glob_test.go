@@ -0,0 +1,48 @@
+// Copyright 2025 Stacklok, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package glob
+
+import "testing"
+
+func TestMatch(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{pattern: "charts/*/README.md", name: "charts/app/README.md", want: true},
+		{pattern: "charts/*/README.md", name: "charts/app/sub/README.md"},
+		{pattern: "**/README.md", name: "README.md", want: true},
+		{pattern: "**/README.md", name: "charts/app/sub/README.md", want: true},
+		{pattern: "config/**", name: "config/crd/bases/x.yaml", want: true},
+		{pattern: "config/**/*.yaml", name: "config/x.json"},
+		{pattern: "docs/**/guides/**/*.md", name: "docs/guides/a.md", want: true},
+		{pattern: "docs/**/guides/**/*.md", name: "docs/v1/guides/setup/a.md", want: true},
+		{pattern: "docs/**/guides/**/*.md", name: "docs/v1/setup/a.md"},
+		{pattern: "/repo/**/*.md", name: "/repo/docs/a.md", want: true},
+		{pattern: "*.md", name: "docs/a.md"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := Match(tt.pattern, tt.name); got != tt.want {
+				t.Errorf("Match(%q, %q) = %v, want %v", tt.pattern, tt.name, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,285 @@
+// Copyright 2025 Stacklok, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package hooks runs user-defined commands before and after the version bump
+// and reports the files they changed.
+package hooks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/stacklok/releaseo/internal/git"
+	"github.com/stacklok/releaseo/internal/glob"
+)
+
+// Hook phases.
+const (
+	// PhasePre hooks run before any version file is updated.
+	PhasePre = "pre"
+	// PhasePost hooks run after all version files have been updated.
+	PhasePost = "post"
+)
+
+// PresetHelmDocs runs helm-docs to regenerate chart documentation.
+const PresetHelmDocs = "helm-docs"
+
+// presets maps preset names to the command they run.
+var presets = map[string][]string{
+	PresetHelmDocs: {"helm-docs"},
+}
+
+// DefaultTimeout is how long a hook may run when it has no timeout configured.
+const DefaultTimeout = 10 * time.Minute
+
+// Environment variables set for every hook.
+const (
+	EnvOldVersion = "RELEASEO_OLD_VERSION"
+	EnvNewVersion = "RELEASEO_NEW_VERSION"
+	EnvBumpType   = "RELEASEO_BUMP_TYPE"
+)
+
+// Hook is a command run as part of the release. The command is executed directly,
+// without a shell, so arguments are never subject to word splitting or globbing.
+type Hook struct {
+	// Name identifies the hook in logs (optional, defaults to the command name).
+	Name string `json:"name"`
+	// Phase is PhasePre or PhasePost (optional, defaults to PhasePost).
+	Phase string `json:"phase"`
+	// Command is the argv of the command to run. Mutually exclusive with Preset.
+	Command []string `json:"command"`
+	// Preset selects a built-in command, such as PresetHelmDocs. Mutually exclusive with Command.
+	Preset string `json:"preset"`
+	// Args are extra arguments appended to the preset's command.
+	Args []string `json:"args"`
+	// Dir is the working directory, relative to the repository root (optional).
+	Dir string `json:"dir"`
+	// Env holds additional environment variables for the command.
+	Env map[string]string `json:"env"`
+	// Timeout is a Go duration such as "5m" (optional, defaults to DefaultTimeout).
+	Timeout string `json:"timeout"`
+	// Outputs are globs, relative to the repository root, of the files the hook may change.
+	// "**" matches any number of directories. Changes outside them are not committed.
+	// If empty, every file the hook changes is committed.
+	Outputs []string `json:"outputs"`
+}
+
+// HelmDocs returns the helm-docs preset hook with the given arguments.
+func HelmDocs(args []string) Hook {
+	return Hook{Name: PresetHelmDocs, Preset: PresetHelmDocs, Args: args}
+}
+
+// Validate checks that the hook is well-formed.
+func (h *Hook) Validate() error {
+	switch {
+	case len(h.Command) > 0 && h.Preset != "":
+		return fmt.Errorf("command and preset are mutually exclusive")
+	case len(h.Command) == 0 && h.Preset == "":
+		return fmt.Errorf("command or preset is required")
+	case h.Preset != "" && presets[h.Preset] == nil:
+		return fmt.Errorf("unknown preset %q", h.Preset)
+	case len(h.Command) > 0 && len(h.Args) > 0:
+		return fmt.Errorf("args can only be used with a preset; add them to command instead")
+	case len(h.Command) > 0 && h.Command[0] == "":
+		return fmt.Errorf("command name is empty")
+	}
+
+	if h.Phase != "" && h.Phase != PhasePre && h.Phase != PhasePost {
+		return fmt.Errorf("invalid phase %q (expected %s or %s)", h.Phase, PhasePre, PhasePost)
+	}
+	if filepath.IsAbs(h.Dir) || strings.HasPrefix(path.Clean(filepath.ToSlash(h.Dir)), "../") {
+		return fmt.Errorf("dir %q must be relative to the repository root", h.Dir)
+	}
+	if _, err := h.timeout(); err != nil {
+		return err
+	}
+	for _, glob := range h.Outputs {
+		if _, err := path.Match(glob, ""); err != nil {
+			return fmt.Errorf("invalid output glob %q: %w", glob, err)
+		}
+	}
+	return nil
+}
+
+// PhaseOrDefault returns the hook's phase, defaulting to PhasePost.
+func (h *Hook) PhaseOrDefault() string {
+	if h.Phase == "" {
+		return PhasePost
+	}
+	return h.Phase
+}
+
+// DisplayName returns the hook's name, or its command name if it has none.
+func (h *Hook) DisplayName() string {
+	if h.Name != "" {
+		return h.Name
+	}
+	if argv := h.argv(); len(argv) > 0 {
+		return argv[0]
+	}
+	return "hook"
+}
+
+// argv returns the command and arguments to execute.
+func (h *Hook) argv() []string {
+	if h.Preset != "" {
+		return append(append([]string{}, presets[h.Preset]...), h.Args...)
+	}
+	return h.Command
+}
+
+// timeout returns the configured timeout, or DefaultTimeout if unset.
+func (h *Hook) timeout() (time.Duration, error) {
+	if h.Timeout == "" {
+		return DefaultTimeout, nil
+	}
+	d, err := time.ParseDuration(h.Timeout)
+	if err != nil {
+		return 0, fmt.Errorf("invalid timeout %q: %w", h.Timeout, err)
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("timeout %q must be positive", h.Timeout)
+	}
+	return d, nil
+}
+
+// Vars describe the release a hook runs for. They are exported to the hook's environment.
+type Vars struct {
+	OldVersion string
+	NewVersion string
+	BumpType   string
+}
+
+// Result lists the changes made by a hook.
+type Result struct {
+	// Changes are the files the hook changed that match its outputs.
+	Changes []git.Change
+	// Ignored are the files the hook changed outside its outputs; they are not committed.
+	Ignored []string
+}
+
+// Runner defines the interface for running hooks.
+type Runner interface {
+	// Run executes the hook and returns the files it changed.
+	Run(ctx context.Context, hook Hook, vars Vars) (*Result, error)
+}
+
+// DefaultRunner runs hooks as child processes in a git working tree.
+type DefaultRunner struct {
+	// RepoDir is the root of the repository (optional, defaults to the current directory).
+	RepoDir string
+	// Stdout and Stderr receive the hook's output (optional, default to os.Stdout and os.Stderr).
+	Stdout io.Writer
+	Stderr io.Writer
+}
+
+// Ensure DefaultRunner implements Runner at compile time.
+var _ Runner = (*DefaultRunner)(nil)
+
+// Run executes the hook and returns the files it changed. The working tree is
+// snapshotted before and after the hook, so files that were already dirty are
+// only reported if the hook changed them again.
+func (r *DefaultRunner) Run(ctx context.Context, hook Hook, vars Vars) (*Result, error) {
+	if err := hook.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid hook %s: %w", hook.DisplayName(), err)
+	}
+	timeout, _ := hook.timeout()
+
+	before, err := git.TakeSnapshot(r.RepoDir)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	argv := hook.argv()
+	cmd := exec.CommandContext(ctx, argv[0], argv[1:]...) //nolint:gosec // hooks are configured by the workflow author
+	cmd.Dir = filepath.Join(r.RepoDir, hook.Dir)
+	cmd.Env = hookEnv(os.Environ(), hook.Env, vars)
+	cmd.Stdout = writerOr(r.Stdout, os.Stdout)
+	cmd.Stderr = writerOr(r.Stderr, os.Stderr)
+	// Do not wait forever for grandchildren holding the output pipes after a timeout
+	cmd.WaitDelay = 10 * time.Second
+
+	if err := cmd.Run(); err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("hook %s timed out after %s", hook.DisplayName(), timeout)
+		}
+		return nil, fmt.Errorf("hook %s: %w", hook.DisplayName(), err)
+	}
+
+	after, err := git.TakeSnapshot(r.RepoDir)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{}
+	for _, change := range after.ChangesSince(before) {
+		if len(hook.Outputs) == 0 || matchAny(hook.Outputs, change.Path) {
+			result.Changes = append(result.Changes, change)
+		} else {
+			result.Ignored = append(result.Ignored, change.Path)
+		}
+	}
+	return result, nil
+}
+
+// hookEnv returns the environment for a hook: the inherited environment, the release
+// variables, then the hook's own variables in a stable order.
+func hookEnv(base []string, extra map[string]string, vars Vars) []string {
+	env := append([]string{}, base...)
+	env = append(env,
+		EnvOldVersion+"="+vars.OldVersion,
+		EnvNewVersion+"="+vars.NewVersion,
+		EnvBumpType+"="+vars.BumpType,
+	)
+
+	keys := make([]string, 0, len(extra))
+	for k := range extra {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		env = append(env, k+"="+extra[k])
+	}
+	return env
+}
+
+// writerOr returns w, or fallback if w is nil.
+func writerOr(w, fallback io.Writer) io.Writer {
+	if w == nil {
+		return fallback
+	}
+	return w
+}
+
+// matchAny reports whether name matches any of the globs.
+func matchAny(globs []string, name string) bool {
+	for _, pattern := range globs {
+		if glob.Match(pattern, name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,231 @@
+// Copyright 2025 Stacklok, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hooks
+
+import (
+	"context"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stacklok/releaseo/internal/git"
+)
+
+func TestHook_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		hook    Hook
+		wantErr string
+	}{
+		{name: "command", hook: Hook{Command: []string{"go", "generate", "./..."}}},
+		{name: "preset with args", hook: HelmDocs([]string{"--chart-search-root=charts"})},
+		{
+			name: "all fields",
+			hook: Hook{
+				Name: "manifests", Phase: PhasePre, Command: []string{"make", "manifests"}, Dir: "operator",
+				Env: map[string]string{"GOFLAGS": "-mod=mod"}, Timeout: "2m", Outputs: []string{"config/**/*.yaml"},
+			},
+		},
+		{name: "empty", hook: Hook{}, wantErr: "command or preset is required"},
+		{name: "both", hook: Hook{Command: []string{"make"}, Preset: PresetHelmDocs}, wantErr: "mutually exclusive"},
+		{name: "unknown preset", hook: Hook{Preset: "goreleaser"}, wantErr: "unknown preset"},
+		{name: "args without preset", hook: Hook{Command: []string{"make"}, Args: []string{"x"}}, wantErr: "args"},
+		{name: "empty command name", hook: Hook{Command: []string{""}}, wantErr: "command name is empty"},
+		{name: "invalid phase", hook: Hook{Command: []string{"make"}, Phase: "during"}, wantErr: "invalid phase"},
+		{name: "absolute dir", hook: Hook{Command: []string{"make"}, Dir: "/tmp"}, wantErr: "relative"},
+		{name: "dir escaping repo", hook: Hook{Command: []string{"make"}, Dir: "a/../../b"}, wantErr: "relative"},
+		{name: "invalid timeout", hook: Hook{Command: []string{"make"}, Timeout: "soon"}, wantErr: "invalid timeout"},
+		{name: "negative timeout", hook: Hook{Command: []string{"make"}, Timeout: "-1s"}, wantErr: "must be positive"},
+		{name: "invalid glob", hook: Hook{Command: []string{"make"}, Outputs: []string{"[a-"}}, wantErr: "invalid output glob"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := tt.hook.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestHook_Defaults(t *testing.T) {
+	t.Parallel()
+
+	helmDocs := HelmDocs([]string{"-c", "charts"})
+	if got := helmDocs.argv(); !reflect.DeepEqual(got, []string{"helm-docs", "-c", "charts"}) {
+		t.Errorf("helm-docs argv = %v", got)
+	}
+	if helmDocs.PhaseOrDefault() != PhasePost {
+		t.Errorf("PhaseOrDefault() = %q, want %q", helmDocs.PhaseOrDefault(), PhasePost)
+	}
+
+	unnamed := Hook{Command: []string{"npm", "install", "--package-lock-only"}}
+	if unnamed.DisplayName() != "npm" {
+		t.Errorf("DisplayName() = %q, want npm", unnamed.DisplayName())
+	}
+}
+
+func TestMatchAny(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		globs []string
+		name  string
+		want  bool
+	}{
+		{globs: []string{"charts/*/README.md"}, name: "charts/app/README.md", want: true},
+		{globs: []string{"charts/*/README.md"}, name: "charts/app/sub/README.md"},
+		{globs: []string{"**/README.md"}, name: "README.md", want: true},
+		{globs: []string{"**/README.md"}, name: "charts/app/sub/README.md", want: true},
+		{globs: []string{"config/**"}, name: "config/crd/bases/x.yaml", want: true},
+		{globs: []string{"config/**/*.yaml"}, name: "config/x.json"},
+		{globs: []string{"*.md"}, name: "docs/a.md"},
+		{globs: []string{"go.sum", "*.md"}, name: "go.sum", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.globs, ",")+" "+tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := matchAny(tt.globs, tt.name); got != tt.want {
+				t.Errorf("matchAny(%v, %q) = %v, want %v", tt.globs, tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHookEnv(t *testing.T) {
+	t.Parallel()
+
+	env := hookEnv([]string{"PATH=/bin"}, map[string]string{"B": "2", "A": "1"},
+		Vars{OldVersion: "1.0.0", NewVersion: "1.1.0", BumpType: "minor"})
+
+	want := []string{
+		"PATH=/bin",
+		"RELEASEO_OLD_VERSION=1.0.0",
+		"RELEASEO_NEW_VERSION=1.1.0",
+		"RELEASEO_BUMP_TYPE=minor",
+		"A=1",
+		"B=2",
+	}
+	if !reflect.DeepEqual(env, want) {
+		t.Errorf("hookEnv() = %v, want %v", env, want)
+	}
+}
+
+// newTestRepo creates a git repository with one commit, skipping the test if git or sh is unavailable.
+func newTestRepo(t *testing.T) string {
+	t.Helper()
+	for _, tool := range []string{"git", "sh"} {
+		if _, err := exec.LookPath(tool); err != nil {
+			t.Skipf("%s is not installed", tool)
+		}
+	}
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "VERSION"), []byte("1.0.0\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "charts", "app"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for _, args := range [][]string{{"init", "-q"}, {"add", "."}, {"commit", "-q", "--allow-empty", "-m", "initial"}} {
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	return dir
+}
+
+func TestDefaultRunner_Run(t *testing.T) {
+	t.Parallel()
+
+	dir := newTestRepo(t)
+	// Left behind by an earlier workflow step
+	if err := os.WriteFile(filepath.Join(dir, "leftover.txt"), []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	runner := &DefaultRunner{RepoDir: dir, Stdout: io.Discard, Stderr: io.Discard}
+	result, err := runner.Run(context.Background(), Hook{
+		Name:    "docs",
+		Command: []string{"sh", "-c", `echo "$RELEASEO_NEW_VERSION $GREETING" > README.md && echo x > scratch.log`},
+		Dir:     "charts/app",
+		Env:     map[string]string{"GREETING": "hello"},
+		Outputs: []string{"charts/**/README.md"},
+	}, Vars{OldVersion: "1.0.0", NewVersion: "1.1.0", BumpType: "minor"})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	wantChanges := []git.Change{{Kind: git.Added, Path: "charts/app/README.md"}}
+	if !reflect.DeepEqual(result.Changes, wantChanges) {
+		t.Errorf("Changes = %+v, want %+v", result.Changes, wantChanges)
+	}
+	if !reflect.DeepEqual(result.Ignored, []string{"charts/app/scratch.log"}) {
+		t.Errorf("Ignored = %v, want [charts/app/scratch.log]", result.Ignored)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, "charts", "app", "README.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "1.1.0 hello\n" {
+		t.Errorf("hook output = %q, want the new version and custom env", content)
+	}
+}
+
+func TestDefaultRunner_RunErrors(t *testing.T) {
+	t.Parallel()
+
+	dir := newTestRepo(t)
+	runner := &DefaultRunner{RepoDir: dir, Stdout: io.Discard, Stderr: io.Discard}
+
+	tests := []struct {
+		name    string
+		hook    Hook
+		wantErr string
+	}{
+		{name: "failing command", hook: Hook{Name: "fail", Command: []string{"sh", "-c", "exit 3"}}, wantErr: "hook fail: exit status 3"},
+		{name: "timeout", hook: Hook{Command: []string{"sleep", "5"}, Timeout: "50ms"}, wantErr: "timed out after 50ms"},
+		{name: "invalid hook", hook: Hook{}, wantErr: "invalid hook"},
+		{name: "missing command", hook: Hook{Command: []string{"releaseo-no-such-command"}}, wantErr: "executable file not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := runner.Run(context.Background(), tt.hook, Vars{})
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Run() error = %v, want to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,291 @@
+// Copyright 2025 Stacklok, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package registry resolves container image tags to digests through the
+// OCI distribution (Docker registry HTTP API v2) protocol.
+package registry
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// DefaultTimeout bounds a single digest lookup, including authentication.
+const DefaultTimeout = 30 * time.Second
+
+// Docker Hub is addressed as docker.io in image references but served from another host.
+const (
+	dockerHub         = "docker.io"
+	dockerHubEndpoint = "https://registry-1.docker.io"
+)
+
+// manifestMediaTypes are accepted when resolving a tag, so that multi-platform images
+// resolve to the digest of their index, the same digest `docker pull` reports.
+var manifestMediaTypes = []string{
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+}
+
+// ErrNotFound is returned when the registry has no manifest for the reference.
+var ErrNotFound = errors.New("image not found")
+
+var (
+	digestPattern    = regexp.MustCompile(`^sha256:[0-9a-f]{64}$`)
+	challengePattern = regexp.MustCompile(`(\w+)="([^"]*)"`)
+)
+
+// Reference is a parsed image reference.
+type Reference struct {
+	// Registry is the registry host, such as ghcr.io or docker.io.
+	Registry string
+	// Repository is the repository path within the registry.
+	Repository string
+	// Tag is the image tag.
+	Tag string
+}
+
+// ParseReference parses an image reference of the form [registry/]repository:tag.
+// A digest already present in the reference (@sha256:...) is ignored, and references
+// without a registry refer to Docker Hub, as with docker pull.
+func ParseReference(ref string) (Reference, error) {
+	name, _, _ := strings.Cut(ref, "@")
+
+	slash := strings.LastIndex(name, "/")
+	colon := strings.LastIndex(name, ":")
+	if colon <= slash {
+		return Reference{}, fmt.Errorf("image reference %q has no tag", ref)
+	}
+	r := Reference{Repository: name[:colon], Tag: name[colon+1:]}
+	if r.Repository == "" || r.Tag == "" {
+		return Reference{}, fmt.Errorf("invalid image reference %q", ref)
+	}
+
+	r.Registry = dockerHub
+	if host, rest, ok := strings.Cut(r.Repository, "/"); ok && (strings.ContainsAny(host, ".:") || host == "localhost") {
+		r.Registry, r.Repository = host, rest
+	}
+	if r.Registry == dockerHub && !strings.Contains(r.Repository, "/") {
+		r.Repository = "library/" + r.Repository
+	}
+	return r, nil
+}
+
+// String returns the reference in registry/repository:tag form.
+func (r Reference) String() string {
+	return r.Registry + "/" + r.Repository + ":" + r.Tag
+}
+
+// Client resolves image tags to digests. Anonymous pull tokens are requested
+// automatically; Username and Password are used when the registry asks for them.
+type Client struct {
+	// HTTPClient is used for all requests (optional, defaults to http.DefaultClient).
+	HTTPClient *http.Client
+	// Endpoint replaces the registry's URL, such as https://mirror.example.com or the
+	// address of a test registry (optional).
+	Endpoint string
+	// Username and Password authenticate to the registry (optional).
+	Username string
+	Password string
+}
+
+// Digest returns the manifest digest (sha256:...) the registry serves for the tag of ref.
+// It returns an error wrapping ErrNotFound if the tag does not exist.
+func (c *Client) Digest(ctx context.Context, ref string) (string, error) {
+	r, err := ParseReference(ref)
+	if err != nil {
+		return "", err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, DefaultTimeout)
+	defer cancel()
+
+	manifestURL := fmt.Sprintf("%s/v2/%s/manifests/%s", c.baseURL(r.Registry), r.Repository, url.PathEscape(r.Tag))
+
+	resp, err := c.manifest(ctx, http.MethodHead, manifestURL, "")
+	if err != nil {
+		return "", err
+	}
+	var auth string
+	if resp.StatusCode == http.StatusUnauthorized {
+		resp.Body.Close()
+		if auth, err = c.authorize(ctx, resp.Header.Get("WWW-Authenticate"), r); err != nil {
+			return "", fmt.Errorf("authenticating to %s: %w", r.Registry, err)
+		}
+		if resp, err = c.manifest(ctx, http.MethodHead, manifestURL, auth); err != nil {
+			return "", err
+		}
+	}
+	defer resp.Body.Close()
+	return c.digestFromResponse(ctx, resp, r, manifestURL, auth)
+}
+
+// digestFromResponse returns the digest of a manifest HEAD response, falling back to
+// fetching and hashing the manifest if the registry does not report it.
+func (c *Client) digestFromResponse(ctx context.Context, resp *http.Response, r Reference, manifestURL, auth string) (string, error) {
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return "", fmt.Errorf("%s: %w", r, ErrNotFound)
+	default:
+		return "", fmt.Errorf("looking up %s: unexpected status %s", r, resp.Status)
+	}
+
+	if digest := resp.Header.Get("Docker-Content-Digest"); digest != "" {
+		if !digestPattern.MatchString(digest) {
+			return "", fmt.Errorf("looking up %s: unsupported digest %q", r, digest)
+		}
+		return digest, nil
+	}
+
+	get, err := c.manifest(ctx, http.MethodGet, manifestURL, auth)
+	if err != nil {
+		return "", err
+	}
+	defer get.Body.Close()
+	if get.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("fetching manifest of %s: unexpected status %s", r, get.Status)
+	}
+	h := sha256.New()
+	if _, err := io.Copy(h, get.Body); err != nil {
+		return "", fmt.Errorf("fetching manifest of %s: %w", r, err)
+	}
+	return "sha256:" + hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// manifest requests a manifest with the given method and Authorization header.
+func (c *Client) manifest(ctx context.Context, method, manifestURL, auth string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, manifestURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", strings.Join(manifestMediaTypes, ", "))
+	if auth != "" {
+		req.Header.Set("Authorization", auth)
+	}
+	resp, err := c.httpClient().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("requesting manifest: %w", err)
+	}
+	return resp, nil
+}
+
+// authorize answers a WWW-Authenticate challenge and returns the Authorization header to send.
+func (c *Client) authorize(ctx context.Context, challenge string, r Reference) (string, error) {
+	scheme, params, _ := strings.Cut(challenge, " ")
+	switch strings.ToLower(scheme) {
+	case "basic":
+		if c.Username == "" {
+			return "", fmt.Errorf("registry requires credentials")
+		}
+		req := &http.Request{Header: http.Header{}}
+		req.SetBasicAuth(c.Username, c.Password)
+		return req.Header.Get("Authorization"), nil
+	case "bearer":
+		token, err := c.token(ctx, params, r)
+		if err != nil {
+			return "", err
+		}
+		return "Bearer " + token, nil
+	default:
+		return "", fmt.Errorf("unsupported authentication challenge %q", challenge)
+	}
+}
+
+// token requests a pull token from the realm of a Bearer challenge.
+func (c *Client) token(ctx context.Context, params string, r Reference) (string, error) {
+	values := map[string]string{}
+	for _, m := range challengePattern.FindAllStringSubmatch(params, -1) {
+		values[strings.ToLower(m[1])] = m[2]
+	}
+	if values["realm"] == "" {
+		return "", fmt.Errorf("bearer challenge without realm")
+	}
+
+	realm, err := url.Parse(values["realm"])
+	if err != nil {
+		return "", fmt.Errorf("invalid realm %q: %w", values["realm"], err)
+	}
+	query := realm.Query()
+	if values["service"] != "" {
+		query.Set("service", values["service"])
+	}
+	scope := values["scope"]
+	if scope == "" {
+		scope = "repository:" + r.Repository + ":pull"
+	}
+	query.Set("scope", scope)
+	realm.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	if c.Username != "" {
+		req.SetBasicAuth(c.Username, c.Password)
+	}
+	resp, err := c.httpClient().Do(req)
+	if err != nil {
+		return "", fmt.Errorf("requesting token: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("requesting token: unexpected status %s", resp.Status)
+	}
+
+	var body struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("decoding token: %w", err)
+	}
+	if body.Token != "" {
+		return body.Token, nil
+	}
+	if body.AccessToken != "" {
+		return body.AccessToken, nil
+	}
+	return "", fmt.Errorf("token response has no token")
+}
+
+// baseURL returns the URL the registry's API is served from.
+func (c *Client) baseURL(registry string) string {
+	switch {
+	case c.Endpoint != "":
+		return strings.TrimSuffix(c.Endpoint, "/")
+	case registry == dockerHub:
+		return dockerHubEndpoint
+	default:
+		return "https://" + registry
+	}
+}
+
+func (c *Client) httpClient() *http.Client {
+	if c.HTTPClient != nil {
+		return c.HTTPClient
+	}
+	return http.DefaultClient
+}
@@ -0,0 +1,183 @@
+// Copyright 2025 Stacklok, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestParseReference(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		ref     string
+		want    Reference
+		wantErr bool
+	}{
+		{ref: "ghcr.io/acme/app:v1.2.3", want: Reference{Registry: "ghcr.io", Repository: "acme/app", Tag: "v1.2.3"}},
+		{ref: "localhost:5000/app:1.0.0", want: Reference{Registry: "localhost:5000", Repository: "app", Tag: "1.0.0"}},
+		{ref: "acme/app:1.0.0", want: Reference{Registry: "docker.io", Repository: "acme/app", Tag: "1.0.0"}},
+		{ref: "nginx:1.27", want: Reference{Registry: "docker.io", Repository: "library/nginx", Tag: "1.27"}},
+		{
+			ref:  "ghcr.io/acme/app:v1.2.3@sha256:aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa",
+			want: Reference{Registry: "ghcr.io", Repository: "acme/app", Tag: "v1.2.3"},
+		},
+		{ref: "localhost:5000/app", wantErr: true},
+		{ref: "ghcr.io/acme/app", wantErr: true},
+		{ref: ":1.0.0", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			t.Parallel()
+			got, err := ParseReference(tt.ref)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseReference() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseReference() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// testRegistry is a stand-in registry serving one image, acme/app:v1.0.0.
+type testRegistry struct {
+	manifest     string
+	auth         string // "bearer", "basic" or "" for none
+	omitDigest   bool
+	tokenQueries []string
+}
+
+const testToken = "pull-token"
+
+func (reg *testRegistry) handler(t *testing.T) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /token", func(w http.ResponseWriter, r *http.Request) {
+		reg.tokenQueries = append(reg.tokenQueries, r.URL.RawQuery)
+		fmt.Fprintf(w, `{"token": %q}`, testToken)
+	})
+	mux.HandleFunc("/v2/acme/app/manifests/{tag}", func(w http.ResponseWriter, r *http.Request) {
+		if !strings.Contains(r.Header.Get("Accept"), "application/vnd.oci.image.index.v1+json") {
+			t.Errorf("Accept = %q, want OCI index among the accepted types", r.Header.Get("Accept"))
+		}
+		switch reg.auth {
+		case "bearer":
+			if r.Header.Get("Authorization") != "Bearer "+testToken {
+				w.Header().Set("WWW-Authenticate",
+					`Bearer realm="http://`+r.Host+`/token",service="test-registry",scope="repository:acme/app:pull"`)
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+		case "basic":
+			if user, pass, ok := r.BasicAuth(); !ok || user != "bot" || pass != "secret" {
+				w.Header().Set("WWW-Authenticate", `Basic realm="test-registry"`)
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+		}
+		if r.PathValue("tag") != "v1.0.0" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if !reg.omitDigest {
+			w.Header().Set("Docker-Content-Digest", manifestDigest(reg.manifest))
+		}
+		if r.Method == http.MethodGet {
+			fmt.Fprint(w, reg.manifest)
+		}
+	})
+	return mux
+}
+
+func manifestDigest(manifest string) string {
+	sum := sha256.Sum256([]byte(manifest))
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+func TestClient_Digest(t *testing.T) {
+	t.Parallel()
+
+	const manifest = `{"schemaVersion": 2, "mediaType": "application/vnd.oci.image.index.v1+json", "manifests": []}`
+
+	tests := []struct {
+		name         string
+		registry     *testRegistry
+		username     string
+		ref          string
+		wantNotFound bool
+		wantErr      string
+	}{
+		{name: "anonymous", registry: &testRegistry{}, ref: "ghcr.io/acme/app:v1.0.0"},
+		{name: "bearer token", registry: &testRegistry{auth: "bearer"}, ref: "ghcr.io/acme/app:v1.0.0"},
+		{name: "basic auth", registry: &testRegistry{auth: "basic"}, username: "bot", ref: "ghcr.io/acme/app:v1.0.0"},
+		{name: "digest computed from manifest", registry: &testRegistry{omitDigest: true}, ref: "ghcr.io/acme/app:v1.0.0"},
+		{name: "tag not pushed yet", registry: &testRegistry{auth: "bearer"}, ref: "ghcr.io/acme/app:v1.1.0", wantNotFound: true},
+		{name: "basic auth without credentials", registry: &testRegistry{auth: "basic"}, ref: "ghcr.io/acme/app:v1.0.0", wantErr: "requires credentials"},
+		{name: "no tag", registry: &testRegistry{}, ref: "ghcr.io/acme/app", wantErr: "has no tag"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			tt.registry.manifest = manifest
+			server := httptest.NewServer(tt.registry.handler(t))
+			t.Cleanup(server.Close)
+
+			client := &Client{HTTPClient: server.Client(), Endpoint: server.URL, Username: tt.username, Password: "secret"}
+			got, err := client.Digest(context.Background(), tt.ref)
+
+			switch {
+			case tt.wantNotFound:
+				if !errors.Is(err, ErrNotFound) {
+					t.Errorf("Digest() error = %v, want ErrNotFound", err)
+				}
+			case tt.wantErr != "":
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Digest() error = %v, want to contain %q", err, tt.wantErr)
+				}
+			case err != nil:
+				t.Fatalf("Digest() error = %v", err)
+			case got != manifestDigest(manifest):
+				t.Errorf("Digest() = %s, want %s", got, manifestDigest(manifest))
+			}
+		})
+	}
+}
+
+func TestClient_DigestTokenScope(t *testing.T) {
+	t.Parallel()
+
+	reg := &testRegistry{auth: "bearer", manifest: "{}"}
+	server := httptest.NewServer(reg.handler(t))
+	t.Cleanup(server.Close)
+
+	client := &Client{HTTPClient: server.Client(), Endpoint: server.URL}
+	if _, err := client.Digest(context.Background(), "ghcr.io/acme/app:v1.0.0"); err != nil {
+		t.Fatalf("Digest() error = %v", err)
+	}
+	if len(reg.tokenQueries) != 1 || reg.tokenQueries[0] != "scope=repository%3Aacme%2Fapp%3Apull&service=test-registry" {
+		t.Errorf("token requests = %v, want one for the pull scope", reg.tokenQueries)
+	}
+}
@@ -0,0 +1,206 @@
+// Copyright 2025 Stacklok, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package templates renders the release PR branch name, title, body, commit message and milestone.
+package templates
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/stacklok/releaseo/internal/files"
+)
+
+// Default templates, matching the output releaseo has always produced.
+const (
+	DefaultBranch        = "release/v{{ .NewVersion }}"
+	DefaultTitle         = "Release v{{ .NewVersion }}"
+	DefaultCommitMessage = "Update release files"
+	DefaultBody          = "## Release v{{ .NewVersion }}\n\n" +
+		"### Version Bump\n\n" +
+		"**{{ .BumpType }}** release\n\n" +
+		"### Files Updated\n\n" +
+		"{{ if .VersionFile }}- `{{ .VersionFile }}`\n{{ end }}" +
+		"{{ range .VersionFiles }}- {{ .Summary }}\n{{ end }}" +
+		"{{ if .RanHelmDocs }}- Helm chart docs (via helm-docs)\n{{ end }}" +
+		"{{ if .Changelog }}\n### Changelog\n\n{{ .Changelog }}\n{{ end }}" +
+		"\n### Next Steps\n\n" +
+		"1. Review this PR\n" +
+		"2. Merge to main\n" +
+		"3. Release automation will handle the rest\n" +
+		"\n### Checklist\n\n" +
+		"- [ ] Version bump is correct\n" +
+		"- [ ] All CI checks pass\n"
+)
+
+// Config holds the raw template strings. Empty fields fall back to the defaults;
+// there is no default milestone, so an empty Milestone means none is set.
+type Config struct {
+	Branch        string
+	Title         string
+	Body          string
+	CommitMessage string
+	Milestone     string
+}
+
+// Data is the value templates are executed against.
+type Data struct {
+	OldVersion   string
+	NewVersion   string
+	BumpType     string
+	Component    string
+	Actor        string
+	Changelog    string
+	VersionFile  string
+	VersionFiles []files.VersionFileConfig
+	Files        []string
+	RanHelmDocs  bool
+}
+
+// Rendered holds the output of executing every template.
+type Rendered struct {
+	Branch        string
+	Title         string
+	Body          string
+	CommitMessage string
+	Milestone     string
+}
+
+// Set is a parsed set of release templates.
+type Set struct {
+	branch        *template.Template
+	title         *template.Template
+	body          *template.Template
+	commitMessage *template.Template
+	milestone     *template.Template
+}
+
+// sampleData is used to validate templates at startup, so that references to
+// unknown fields fail before any file is modified.
+var sampleData = Data{
+	OldVersion:   "1.2.3",
+	NewVersion:   "1.3.0",
+	BumpType:     "minor",
+	Component:    "component",
+	Actor:        "octocat",
+	Changelog:    "- change",
+	VersionFile:  "VERSION",
+	VersionFiles: []files.VersionFileConfig{{File: "Chart.yaml", Path: "version"}},
+	Files:        []string{"VERSION", "Chart.yaml"},
+}
+
+// New parses and validates the configured templates.
+func New(cfg Config) (*Set, error) {
+	var s Set
+	var err error
+
+	if s.branch, err = parse("branch", cfg.Branch, DefaultBranch); err != nil {
+		return nil, err
+	}
+	if s.title, err = parse("title", cfg.Title, DefaultTitle); err != nil {
+		return nil, err
+	}
+	if s.body, err = parse("body", cfg.Body, DefaultBody); err != nil {
+		return nil, err
+	}
+	if s.commitMessage, err = parse("commit message", cfg.CommitMessage, DefaultCommitMessage); err != nil {
+		return nil, err
+	}
+	if s.milestone, err = parse("milestone", cfg.Milestone, ""); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.Render(sampleData); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// Render executes all templates against data and validates the results.
+func (s *Set) Render(data Data) (*Rendered, error) {
+	var r Rendered
+	var err error
+
+	if r.Branch, err = execute(s.branch, data); err != nil {
+		return nil, err
+	}
+	if r.Title, err = execute(s.title, data); err != nil {
+		return nil, err
+	}
+	if r.Body, err = execute(s.body, data); err != nil {
+		return nil, err
+	}
+	if r.CommitMessage, err = execute(s.commitMessage, data); err != nil {
+		return nil, err
+	}
+	if r.Milestone, err = execute(s.milestone, data); err != nil {
+		return nil, err
+	}
+
+	r.Branch = strings.TrimSpace(r.Branch)
+	r.Title = strings.TrimSpace(r.Title)
+	r.CommitMessage = strings.TrimSpace(r.CommitMessage)
+	r.Milestone = strings.TrimSpace(r.Milestone)
+
+	if err := validateBranchName(r.Branch); err != nil {
+		return nil, fmt.Errorf("branch template: %w", err)
+	}
+	if r.Title == "" {
+		return nil, fmt.Errorf("title template: rendered an empty title")
+	}
+	if r.CommitMessage == "" {
+		return nil, fmt.Errorf("commit message template: rendered an empty message")
+	}
+	return &r, nil
+}
+
+// parse parses text as a template named name, using def when text is empty.
+func parse(name, text, def string) (*template.Template, error) {
+	if text == "" {
+		text = def
+	}
+	t, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s template: %w", name, err)
+	}
+	return t, nil
+}
+
+// execute renders t against data.
+func execute(t *template.Template, data Data) (string, error) {
+	var sb strings.Builder
+	if err := t.Execute(&sb, data); err != nil {
+		return "", fmt.Errorf("executing %s template: %w", t.Name(), err)
+	}
+	return sb.String(), nil
+}
+
+// validateBranchName rejects names git would refuse as a branch (see git-check-ref-format).
+func validateBranchName(name string) error {
+	if name == "" {
+		return fmt.Errorf("rendered an empty branch name")
+	}
+	if strings.HasPrefix(name, "/") || strings.HasSuffix(name, "/") || strings.HasSuffix(name, ".") ||
+		strings.HasSuffix(name, ".lock") || strings.HasPrefix(name, "-") {
+		return fmt.Errorf("invalid branch name %q", name)
+	}
+	if strings.Contains(name, "..") || strings.Contains(name, "//") || strings.Contains(name, "@{") {
+		return fmt.Errorf("invalid branch name %q", name)
+	}
+	if strings.ContainsAny(name, " ~^:?*[\\\t\n") {
+		return fmt.Errorf("invalid branch name %q: contains a forbidden character", name)
+	}
+	return nil
+}
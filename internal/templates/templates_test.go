@@ -0,0 +1,162 @@
+// Copyright 2025 Stacklok, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package templates
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stacklok/releaseo/internal/files"
+)
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr string
+	}{
+		{
+			name: "defaults",
+			cfg:  Config{},
+		},
+		{
+			name: "all fields referenced",
+			cfg: Config{
+				Branch: "release/{{ .Component }}-{{ .NewVersion }}",
+				Title:  "chore(release): {{ .OldVersion }} -> {{ .NewVersion }}",
+				Body: "{{ .BumpType }} by {{ .Actor }}\n{{ .Changelog }}\n" +
+					"{{ range .Files }}{{ . }}\n{{ end }}{{ .VersionFile }}{{ .RanHelmDocs }}",
+				CommitMessage: "chore(release): {{ .NewVersion }}",
+			},
+		},
+		{
+			name:    "syntax error",
+			cfg:     Config{Title: "{{ .NewVersion "},
+			wantErr: "parsing title template",
+		},
+		{
+			name:    "unknown field",
+			cfg:     Config{Body: "{{ .JiraKey }}"},
+			wantErr: "executing body template",
+		},
+		{
+			name:    "branch with spaces",
+			cfg:     Config{Branch: "release {{ .NewVersion }}"},
+			wantErr: "branch template",
+		},
+		{
+			name:    "empty title",
+			cfg:     Config{Title: "{{ if false }}x{{ end }}"},
+			wantErr: "empty title",
+		},
+		{
+			name:    "empty commit message",
+			cfg:     Config{CommitMessage: "  "},
+			wantErr: "empty message",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := New(tt.cfg)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("New() unexpected error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("New() error = %v, want to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSet_Render(t *testing.T) {
+	t.Parallel()
+
+	set, err := New(Config{})
+	if err != nil {
+		t.Fatalf("New() unexpected error = %v", err)
+	}
+
+	got, err := set.Render(Data{
+		OldVersion:  "1.2.3",
+		NewVersion:  "1.3.0",
+		BumpType:    "minor",
+		Changelog:   "- Added a thing",
+		VersionFile: "VERSION",
+		VersionFiles: []files.VersionFileConfig{
+			{File: "chart/Chart.yaml", Path: "appVersion"},
+			{File: "docs/**/*.md", Type: files.TypeMarker},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Render() unexpected error = %v", err)
+	}
+
+	if got.Branch != "release/v1.3.0" {
+		t.Errorf("Branch = %q, want %q", got.Branch, "release/v1.3.0")
+	}
+	if got.Title != "Release v1.3.0" {
+		t.Errorf("Title = %q, want %q", got.Title, "Release v1.3.0")
+	}
+	if got.CommitMessage != "Update release files" {
+		t.Errorf("CommitMessage = %q, want %q", got.CommitMessage, "Update release files")
+	}
+	for _, want := range []string{
+		"## Release v1.3.0\n\n### Version Bump\n\n**minor** release\n\n### Files Updated\n\n- `VERSION`\n",
+		"- `chart/Chart.yaml` (path: `appVersion`)\n",
+		"- `docs/**/*.md` (marked versions)\n",
+		"### Changelog\n\n- Added a thing\n",
+		"### Checklist\n\n- [ ] Version bump is correct\n- [ ] All CI checks pass\n",
+	} {
+		if !strings.Contains(got.Body, want) {
+			t.Errorf("Body = %q, want to contain %q", got.Body, want)
+		}
+	}
+}
+
+func TestValidateBranchName(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		branch  string
+		wantErr bool
+	}{
+		{name: "default style", branch: "release/v1.2.3"},
+		{name: "component style", branch: "release/operator-1.2.3"},
+		{name: "empty", branch: "", wantErr: true},
+		{name: "double dot", branch: "release/v1..2", wantErr: true},
+		{name: "trailing slash", branch: "release/", wantErr: true},
+		{name: "lock suffix", branch: "release.lock", wantErr: true},
+		{name: "colon", branch: "release:1.2.3", wantErr: true},
+		{name: "leading dash", branch: "-release", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if err := validateBranchName(tt.branch); (err != nil) != tt.wantErr {
+				t.Errorf("validateBranchName(%q) error = %v, wantErr %v", tt.branch, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -22,39 +22,120 @@ import (
 	"flag"
 	"fmt"
 	"os"
-	"os/exec"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/stacklok/releaseo/internal/files"
+	"github.com/stacklok/releaseo/internal/git"
 	"github.com/stacklok/releaseo/internal/github"
+	"github.com/stacklok/releaseo/internal/hooks"
+	"github.com/stacklok/releaseo/internal/registry"
+	"github.com/stacklok/releaseo/internal/templates"
 	"github.com/stacklok/releaseo/internal/version"
 )
 
 // Config holds the action configuration.
 type Config struct {
-	BumpType     string
-	VersionFile  string
-	HelmDocsArgs string
-	VersionFiles []files.VersionFileConfig
-	Token        string
-	RepoOwner    string
-	RepoName     string
-	BaseBranch   string
-	TriggeredBy  string
+	BumpType      string
+	VersionFile   string
+	VersionSource files.VersionSourceConfig
+	HelmDocsArgs  string
+	Hooks         []hooks.Hook
+	VersionFiles  []files.VersionFileConfig
+	Token         string
+	RepoOwner     string
+	RepoName      string
+	BaseBranch    string
+	TriggeredBy   string
+	Component     string
+	ChangelogFile string
+	Templates     templates.Config
+	Draft         bool
+	Labels        []string
+	LabelColor    string
+	Reviewers     []string
+	TeamReviewers []string
+	Assignees     []string
+	// AutoMerge is the merge method (merge, squash or rebase) used to auto-merge the PR, or empty to disable.
+	AutoMerge        string
+	AutoMergeTimeout time.Duration
+	// APIURL, UploadURL and CABundle configure access to GitHub Enterprise Server.
+	APIURL    string
+	UploadURL string
+	CABundle  string
+	// App authenticates as a GitHub App installation instead of with Token when App.AppID is set.
+	App github.AppConfig
+	// Retry controls retries of failed GitHub API calls.
+	Retry github.RetryConfig
+	// RegistryURL, RegistryUsername and RegistryPassword configure the container registry
+	// used to resolve image digests for version files with pin_digest.
+	RegistryURL      string
+	RegistryUsername string
+	RegistryPassword string
+}
+
+// versionSourcePath returns the path passed to the VersionReader: the configured
+// source file if any, otherwise the VERSION file.
+func (c Config) versionSourcePath() string {
+	if c.VersionSource.File != "" {
+		return c.VersionSource.File
+	}
+	return c.VersionFile
+}
+
+// allHooks returns the configured hooks in order. --helm-docs-args is shorthand
+// for the helm-docs preset, which runs before the other post hooks.
+func (c Config) allHooks() []hooks.Hook {
+	var all []hooks.Hook
+	if c.HelmDocsArgs != "" {
+		all = append(all, hooks.HelmDocs(strings.Fields(c.HelmDocsArgs)))
+	}
+	return append(all, c.Hooks...)
+}
+
+// runsHelmDocs reports whether helm-docs runs as part of the release.
+func (c Config) runsHelmDocs() bool {
+	return slices.ContainsFunc(c.allHooks(), func(h hooks.Hook) bool {
+		return h.Preset == hooks.PresetHelmDocs
+	})
 }
 
 // Dependencies holds the external dependencies for the release process.
 type Dependencies struct {
 	PRCreator     github.PRCreator
+	PRMerger      github.PRMerger
 	VersionReader files.VersionReader
 	VersionWriter files.VersionWriter
 	YAMLUpdater   files.YAMLUpdater
+	HookRunner    hooks.Runner
 }
 
 // UpdateResult contains the result of updating all version files.
 type UpdateResult struct {
-	HelmDocsFiles []string
-	Errors        []error
+	// HookFiles lists files written by hooks, to be added to the release commit.
+	HookFiles []string
+	// DeletedFiles lists files removed from the working tree, to be deleted in the release commit.
+	DeletedFiles []string
+	Errors       []error
+}
+
+// recordChanges adds the files changed by a hook. A later change to a path
+// supersedes an earlier one, so a file recreated after a deletion is written.
+func (r *UpdateResult) recordChanges(changes []git.Change) {
+	written, deleted := git.Paths(changes)
+	for _, path := range written {
+		r.DeletedFiles = slices.DeleteFunc(r.DeletedFiles, func(p string) bool { return p == path })
+		if !slices.Contains(r.HookFiles, path) {
+			r.HookFiles = append(r.HookFiles, path)
+		}
+	}
+	for _, path := range deleted {
+		r.HookFiles = slices.DeleteFunc(r.HookFiles, func(p string) bool { return p == path })
+		if !slices.Contains(r.DeletedFiles, path) {
+			r.DeletedFiles = append(r.DeletedFiles, path)
+		}
+	}
 }
 
 // HasErrors returns true if any errors occurred during the update.
@@ -71,17 +152,44 @@ func (r *UpdateResult) CombinedError() error {
 }
 
 // NewDefaultDependencies creates a Dependencies struct with real implementations.
-func NewDefaultDependencies(ctx context.Context, token string) (*Dependencies, error) {
-	prCreator, err := github.NewClient(ctx, token)
+func NewDefaultDependencies(ctx context.Context, cfg Config) (*Dependencies, error) {
+	opts := []github.ClientOption{
+		github.WithBaseURL(cfg.APIURL),
+		github.WithUploadURL(cfg.UploadURL),
+		github.WithCABundle(cfg.CABundle),
+		github.WithRetry(cfg.Retry),
+	}
+	if cfg.App.AppID != 0 {
+		fmt.Printf("Authenticating as GitHub App %d\n", cfg.App.AppID)
+		opts = append(opts, github.WithAppAuth(cfg.App))
+	}
+
+	prCreator, err := github.NewClient(ctx, cfg.Token, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("creating GitHub client: %w", err)
 	}
+	if cfg.APIURL != "" {
+		fmt.Printf("Using GitHub API at %s\n", prCreator.Host())
+	}
+
+	versionReader, err := files.NewVersionReader(cfg.VersionSource)
+	if err != nil {
+		return nil, fmt.Errorf("creating version reader: %w", err)
+	}
+
+	digests := &registry.Client{
+		Endpoint: cfg.RegistryURL,
+		Username: cfg.RegistryUsername,
+		Password: cfg.RegistryPassword,
+	}
 
 	return &Dependencies{
 		PRCreator:     prCreator,
-		VersionReader: &files.DefaultVersionReader{},
+		PRMerger:      prCreator,
+		VersionReader: versionReader,
 		VersionWriter: &files.DefaultVersionWriter{},
-		YAMLUpdater:   &files.DefaultYAMLUpdater{},
+		YAMLUpdater:   &files.DefaultYAMLUpdater{Digests: digests, Warn: warning},
+		HookRunner:    &hooks.DefaultRunner{},
 	}, nil
 }
 
@@ -89,7 +197,7 @@ func main() {
 	ctx := context.Background()
 	cfg := parseFlags()
 
-	deps, err := NewDefaultDependencies(ctx, cfg.Token)
+	deps, err := NewDefaultDependencies(ctx, cfg)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
@@ -109,13 +217,13 @@ func run(ctx context.Context, cfg Config, deps *Dependencies) error {
 	}
 
 	// Update all files
-	result := updateAllFiles(cfg, currentVersion, newVersion.String(), deps)
+	result := updateAllFiles(ctx, cfg, currentVersion, newVersion.String(), deps)
 	if result.HasErrors() {
 		return fmt.Errorf("updating files: %w", result.CombinedError())
 	}
 
 	// Create the release PR
-	pr, err := createReleasePR(ctx, cfg, deps.PRCreator, newVersion.String(), result.HelmDocsFiles)
+	pr, err := createReleasePR(ctx, cfg, deps.PRCreator, currentVersion, newVersion.String(), result)
 	if err != nil {
 		return err
 	}
@@ -125,13 +233,19 @@ func run(ctx context.Context, cfg Config, deps *Dependencies) error {
 	setOutput("pr_number", fmt.Sprintf("%d", pr.Number))
 	setOutput("pr_url", pr.URL)
 
+	if cfg.AutoMerge != "" {
+		if err := mergeReleasePR(ctx, cfg, deps.PRMerger, pr); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
 // bumpVersion reads the current version and bumps it according to the bump type.
 // Returns the current version string and the new version.
 func bumpVersion(cfg Config, reader files.VersionReader) (string, *version.Version, error) {
-	currentVersion, err := reader.ReadVersion(cfg.VersionFile)
+	currentVersion, err := reader.ReadVersion(cfg.versionSourcePath())
 	if err != nil {
 		return "", nil, fmt.Errorf("reading version: %w", err)
 	}
@@ -159,93 +273,221 @@ func bumpVersion(cfg Config, reader files.VersionReader) (string, *version.Versi
 	return currentVersion, newVersion, nil
 }
 
-// updateAllFiles updates the VERSION file, custom version files, and runs helm-docs.
-// Returns an UpdateResult containing the list of files modified by helm-docs and any errors.
-func updateAllFiles(cfg Config, currentVersion, newVersion string, deps *Dependencies) *UpdateResult {
+// updateAllFiles runs the pre hooks, updates the VERSION file and custom version files,
+// then runs the post hooks. The VERSION file is skipped when cfg.VersionFile is empty,
+// and nothing is updated if a pre hook fails.
+// Returns an UpdateResult containing the files changed by hooks and any errors.
+func updateAllFiles(ctx context.Context, cfg Config, currentVersion, newVersion string, deps *Dependencies) *UpdateResult {
 	result := &UpdateResult{}
+	vars := hooks.Vars{OldVersion: currentVersion, NewVersion: newVersion, BumpType: cfg.BumpType}
 
-	// Update VERSION file
-	if err := deps.VersionWriter.WriteVersion(cfg.VersionFile, newVersion); err != nil {
-		result.Errors = append(result.Errors, fmt.Errorf("writing version file %s: %w", cfg.VersionFile, err))
-	} else {
-		fmt.Printf("Updated %s\n", cfg.VersionFile)
+	if runHooks(ctx, cfg, hooks.PhasePre, vars, deps.HookRunner, result); result.HasErrors() {
+		return result
 	}
 
-	// Update custom version files
-	for _, vf := range cfg.VersionFiles {
-		if err := deps.YAMLUpdater.UpdateYAMLFile(vf, currentVersion, newVersion); err != nil {
-			result.Errors = append(result.Errors, fmt.Errorf("updating %s at %s: %w", vf.File, vf.Path, err))
+	// Update VERSION file
+	if cfg.VersionFile != "" {
+		if err := deps.VersionWriter.WriteVersion(cfg.VersionFile, newVersion); err != nil {
+			result.Errors = append(result.Errors, fmt.Errorf("writing version file %s: %w", cfg.VersionFile, err))
 		} else {
-			fmt.Printf("Updated %s at path %s\n", vf.File, vf.Path)
+			fmt.Printf("Updated %s\n", cfg.VersionFile)
 		}
 	}
 
-	// Run helm-docs if args are provided
-	if cfg.HelmDocsArgs != "" {
-		helmDocsFiles, err := runHelmDocs(cfg.HelmDocsArgs)
+	// Update custom version files, editing each file once
+	versionFiles := make([]files.VersionFileConfig, len(cfg.VersionFiles))
+	for i, vf := range cfg.VersionFiles {
+		versionFiles[i] = vf.WithAppBump(cfg.BumpType)
+	}
+	for i, err := range deps.YAMLUpdater.UpdateYAMLFiles(versionFiles, currentVersion, newVersion) {
 		if err != nil {
-			result.Errors = append(result.Errors, fmt.Errorf("running helm-docs: %w", err))
+			result.Errors = append(result.Errors, fmt.Errorf("updating %s: %w", versionFiles[i].Location(), err))
 		} else {
-			fmt.Printf("Ran helm-docs successfully\n")
-			if len(helmDocsFiles) > 0 {
-				fmt.Printf("Files modified by helm-docs: %v\n", helmDocsFiles)
-			}
-			result.HelmDocsFiles = helmDocsFiles
+			fmt.Printf("Updated %s\n", versionFiles[i].Location())
 		}
 	}
 
+	if !result.HasErrors() {
+		runHooks(ctx, cfg, hooks.PhasePost, vars, deps.HookRunner, result)
+	}
+
 	return result
 }
 
-// createReleasePR creates the GitHub release PR with all modified files.
+// runHooks runs the hooks of the given phase in order, recording the files they change.
+// It stops at the first hook that fails.
+func runHooks(ctx context.Context, cfg Config, phase string, vars hooks.Vars, runner hooks.Runner, result *UpdateResult) {
+	for _, hook := range cfg.allHooks() {
+		if hook.PhaseOrDefault() != phase {
+			continue
+		}
+
+		name := hook.DisplayName()
+		fmt.Printf("Running %s hook %s\n", phase, name)
+		hookResult, err := runner.Run(ctx, hook, vars)
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Errorf("running hook %s: %w", name, err))
+			return
+		}
+
+		written, deleted := git.Paths(hookResult.Changes)
+		if len(written) > 0 {
+			fmt.Printf("Files written by %s: %v\n", name, written)
+		}
+		if len(deleted) > 0 {
+			fmt.Printf("Files deleted by %s: %v\n", name, deleted)
+		}
+		if len(hookResult.Ignored) > 0 {
+			warning(fmt.Sprintf("hook %s changed files outside its outputs, they will not be committed: %s",
+				name, strings.Join(hookResult.Ignored, ", ")))
+		}
+		result.recordChanges(hookResult.Changes)
+	}
+}
+
+// createReleasePR creates the GitHub release PR with all modified and deleted files.
 func createReleasePR(
 	ctx context.Context,
 	cfg Config,
 	prCreator github.PRCreator,
-	newVersion string,
-	helmDocsFiles []string,
+	currentVersion, newVersion string,
+	update *UpdateResult,
 ) (*github.PRResult, error) {
-	branchName := fmt.Sprintf("release/v%s", newVersion)
-	prTitle := fmt.Sprintf("Release v%s", newVersion)
-	prBody := generatePRBody(newVersion, cfg.BumpType, cfg.VersionFiles, cfg.HelmDocsArgs != "")
+	allFiles := getModifiedFiles(cfg, currentVersion, newVersion)
+	allFiles = append(allFiles, update.HookFiles...)
 
-	allFiles := getModifiedFiles(cfg)
-	allFiles = append(allFiles, helmDocsFiles...)
+	rendered, err := renderTemplates(cfg, currentVersion, newVersion, allFiles)
+	if err != nil {
+		return nil, fmt.Errorf("rendering templates: %w", err)
+	}
 
 	pr, err := prCreator.CreateReleasePR(ctx, github.PRRequest{
-		Owner:       cfg.RepoOwner,
-		Repo:        cfg.RepoName,
-		BaseBranch:  cfg.BaseBranch,
-		HeadBranch:  branchName,
-		Title:       prTitle,
-		Body:        prBody,
-		Files:       allFiles,
-		TriggeredBy: cfg.TriggeredBy,
+		Owner:         cfg.RepoOwner,
+		Repo:          cfg.RepoName,
+		BaseBranch:    cfg.BaseBranch,
+		HeadBranch:    rendered.Branch,
+		Title:         rendered.Title,
+		Body:          rendered.Body,
+		CommitMessage: rendered.CommitMessage,
+		Files:         allFiles,
+		DeletedFiles:  update.DeletedFiles,
+		TriggeredBy:   cfg.TriggeredBy,
+		Draft:         cfg.Draft,
+		Labels:        cfg.Labels,
+		LabelColor:    cfg.LabelColor,
+		Reviewers:     cfg.Reviewers,
+		TeamReviewers: cfg.TeamReviewers,
+		Assignees:     cfg.Assignees,
+		Milestone:     rendered.Milestone,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("creating PR: %w", err)
 	}
 
 	fmt.Printf("\nRelease PR created: %s\n", pr.URL)
+	for _, w := range pr.Warnings {
+		warning(w)
+	}
 	return pr, nil
 }
 
+// mergeReleasePR enables auto-merge on the release PR, falling back to merging it once
+// checks pass, and reports the outcome through the auto_merge_status output.
+// Failing or timed out checks leave the PR open and are reported as a warning, not an error.
+func mergeReleasePR(ctx context.Context, cfg Config, merger github.PRMerger, pr *github.PRResult) error {
+	result, err := merger.MergeReleasePR(ctx, github.MergeRequest{
+		Owner:   cfg.RepoOwner,
+		Repo:    cfg.RepoName,
+		Number:  pr.Number,
+		Method:  cfg.AutoMerge,
+		Timeout: cfg.AutoMergeTimeout,
+	})
+	if err != nil {
+		return fmt.Errorf("merging PR: %w", err)
+	}
+
+	setOutput("auto_merge_status", result.Status)
+	switch result.Status {
+	case github.MergeStatusMerged:
+		setOutput("merge_commit_sha", result.SHA)
+		fmt.Printf("Release PR merged: %s\n", result.SHA)
+	case github.MergeStatusAutoMergeEnabled:
+		fmt.Printf("Auto-merge enabled: %s\n", result.Reason)
+	default:
+		warning(fmt.Sprintf("release PR #%d was not merged (%s): %s", pr.Number, result.Status, result.Reason))
+	}
+	return nil
+}
+
 func parseFlags() Config {
 	cfg := Config{}
-	var versionFilesJSON string
+	var versionFilesJSON, versionSourceJSON, hooksJSON string
+	var labels, reviewers, teamReviewers, assignees string
+	var appPrivateKeyFile string
 
 	flag.StringVar(&cfg.BumpType, "bump-type", "", "Version bump type (major, minor, patch)")
 	flag.StringVar(&cfg.VersionFile, "version-file", "VERSION", "Path to VERSION file")
+	flag.StringVar(&versionSourceJSON, "version-source", "",
+		"JSON object {type, file, path, tag_prefix} selecting where the current version is read from (default: VERSION file)")
 	flag.StringVar(&cfg.HelmDocsArgs, "helm-docs-args", "", "Arguments to pass to helm-docs (if provided, helm-docs will run)")
+	flag.StringVar(&hooksJSON, "hooks", "",
+		"JSON array of {name, phase, command, preset, args, dir, env, timeout, outputs} hooks to run around the version bump")
 	flag.StringVar(&versionFilesJSON, "version-files", "", "JSON array of {file, path, prefix} objects for custom version updates")
 	flag.StringVar(&cfg.Token, "token", "", "GitHub token")
+	flag.Int64Var(&cfg.App.AppID, "app-id", 0, "GitHub App ID to authenticate as instead of --token")
+	flag.Int64Var(&cfg.App.InstallationID, "app-installation-id", 0,
+		"GitHub App installation ID (default: the installation on the repository)")
+	flag.StringVar(&appPrivateKeyFile, "app-private-key-file", "",
+		"Path to the GitHub App private key (default: GITHUB_APP_PRIVATE_KEY environment variable)")
 	flag.StringVar(&cfg.BaseBranch, "base-branch", "main", "Base branch for PR")
+	flag.StringVar(&cfg.APIURL, "api-url", "",
+		"GitHub API URL for GitHub Enterprise Server (default: GITHUB_API_URL, or derived from GITHUB_SERVER_URL)")
+	flag.StringVar(&cfg.UploadURL, "upload-url", "", "GitHub uploads API URL (default: derived from --api-url)")
+	flag.StringVar(&cfg.CABundle, "ca-bundle", "", "Path to a PEM CA bundle to trust in addition to the system roots")
+	flag.IntVar(&cfg.Retry.MaxAttempts, "api-max-attempts", github.DefaultMaxAttempts,
+		"Attempts per GitHub API call before giving up on server errors and rate limits (1 disables retries)")
+	flag.DurationVar(&cfg.Retry.Deadline, "api-retry-deadline", github.DefaultRetryDeadline,
+		"Maximum time to spend retrying GitHub API calls over the whole run")
+	flag.StringVar(&cfg.RegistryURL, "registry-url", "",
+		"Registry API URL used to resolve image digests instead of the registry in each image reference")
+	flag.StringVar(&cfg.RegistryUsername, "registry-username", "",
+		"Username for the container registry (password from the REGISTRY_PASSWORD environment variable)")
+	flag.StringVar(&cfg.Component, "component", "", "Component name exposed to templates as .Component (default: repository name)")
+	flag.StringVar(&cfg.ChangelogFile, "changelog-file", "", "File whose contents are exposed to templates as .Changelog")
+	flag.StringVar(&cfg.Templates.Branch, "branch-template", "", "Go template for the release branch name")
+	flag.StringVar(&cfg.Templates.Title, "title-template", "", "Go template for the PR title")
+	flag.StringVar(&cfg.Templates.Body, "body-template", "", "Go template for the PR body")
+	flag.StringVar(&cfg.Templates.CommitMessage, "commit-message-template", "", "Go template for the release commit message")
+	flag.StringVar(&cfg.Templates.Milestone, "milestone-template", "",
+		"Go template for the milestone title to attach to the PR (created if absent)")
+	flag.BoolVar(&cfg.Draft, "draft", false, "Open the release PR as a draft")
+	flag.StringVar(&cfg.AutoMerge, "auto-merge", "", "Enable auto-merge on the PR with this merge method (merge, squash, rebase)")
+	flag.DurationVar(&cfg.AutoMergeTimeout, "auto-merge-timeout", 30*time.Minute,
+		"How long to wait for checks before giving up when auto-merge is unavailable and the PR is merged directly")
+	flag.StringVar(&labels, "labels", "release", "Comma-separated labels to add to the PR (missing labels are created)")
+	flag.StringVar(&cfg.LabelColor, "label-color", github.DefaultLabelColor, "Hex colour for labels created by releaseo")
+	flag.StringVar(&reviewers, "reviewers", "", "Comma-separated users to request a review from")
+	flag.StringVar(&teamReviewers, "team-reviewers", "", "Comma-separated team slugs to request a review from")
+	flag.StringVar(&assignees, "assignees", "", "Comma-separated users to assign to the PR")
 	flag.Parse()
 
 	cfg.VersionFiles = parseVersionFiles(versionFilesJSON)
+	cfg.Hooks = parseHooks(hooksJSON)
+	cfg.Labels = splitList(labels)
+	cfg.Reviewers = splitList(reviewers)
+	cfg.TeamReviewers = splitList(teamReviewers)
+	cfg.Assignees = splitList(assignees)
+	cfg.VersionSource = parseVersionSource(versionSourceJSON)
+	cfg.VersionFile = resolveVersionFile(cfg.VersionFile, cfg.VersionSource)
 	cfg.Token = resolveToken(cfg.Token)
+	cfg.RegistryPassword = os.Getenv("REGISTRY_PASSWORD")
+	cfg.APIURL = resolveAPIURL(cfg.APIURL, os.Getenv("GITHUB_API_URL"), os.Getenv("GITHUB_SERVER_URL"))
 	cfg.RepoOwner, cfg.RepoName = parseRepository()
 	cfg.TriggeredBy = os.Getenv("GITHUB_ACTOR")
+	if cfg.App.AppID != 0 {
+		cfg.App.Owner, cfg.App.Repo = cfg.RepoOwner, cfg.RepoName
+		cfg.App.PrivateKey = resolveAppPrivateKey(appPrivateKeyFile)
+	}
 
 	validateConfig(cfg)
 
@@ -266,6 +508,60 @@ func parseVersionFiles(jsonStr string) []files.VersionFileConfig {
 	return versionFiles
 }
 
+// parseHooks parses the JSON array of hook configurations.
+func parseHooks(jsonStr string) []hooks.Hook {
+	if jsonStr == "" {
+		return nil
+	}
+
+	var parsed []hooks.Hook
+	if err := json.Unmarshal([]byte(jsonStr), &parsed); err != nil {
+		fmt.Fprintf(os.Stderr, "Error parsing --hooks JSON: %v\n", err)
+		os.Exit(1)
+	}
+	return parsed
+}
+
+// parseVersionSource parses the JSON version source configuration.
+// An empty string selects the default VERSION file source.
+func parseVersionSource(jsonStr string) files.VersionSourceConfig {
+	var source files.VersionSourceConfig
+	if jsonStr == "" {
+		return source
+	}
+
+	if err := json.Unmarshal([]byte(jsonStr), &source); err != nil {
+		fmt.Fprintf(os.Stderr, "Error parsing --version-source JSON: %v\n", err)
+		os.Exit(1)
+	}
+	return source
+}
+
+// resolveVersionFile returns the VERSION file path to keep in sync, or an empty string
+// if there is none. The VERSION file is optional when the version is read from another
+// source, so a missing file is skipped rather than created.
+func resolveVersionFile(path string, source files.VersionSourceConfig) string {
+	if source.IsFile() || path == "" {
+		return path
+	}
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		fmt.Printf("VERSION file %s not found, skipping it (version source: %s)\n", path, source.Type)
+		return ""
+	}
+	return path
+}
+
+// splitList splits a comma-separated list, trimming whitespace and dropping empty entries.
+func splitList(s string) []string {
+	var result []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
 // resolveToken returns the token from the flag or environment variable.
 func resolveToken(flagToken string) string {
 	if flagToken != "" {
@@ -274,6 +570,37 @@ func resolveToken(flagToken string) string {
 	return os.Getenv("GITHUB_TOKEN")
 }
 
+// resolveAppPrivateKey returns the GitHub App private key from the file given
+// by the flag, or from the GITHUB_APP_PRIVATE_KEY environment variable.
+func resolveAppPrivateKey(path string) []byte {
+	if path == "" {
+		return []byte(os.Getenv("GITHUB_APP_PRIVATE_KEY"))
+	}
+
+	key, err := os.ReadFile(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading --app-private-key-file: %v\n", err)
+		os.Exit(1)
+	}
+	return key
+}
+
+// resolveAPIURL returns the GitHub API URL from the flag, falling back to the
+// GITHUB_API_URL and GITHUB_SERVER_URL variables GitHub Actions sets on every runner.
+// An empty result selects github.com.
+func resolveAPIURL(flagURL, apiURLEnv, serverURLEnv string) string {
+	if flagURL != "" {
+		return flagURL
+	}
+	if apiURLEnv != "" {
+		return apiURLEnv
+	}
+	if serverURLEnv != "" && strings.TrimSuffix(serverURLEnv, "/") != "https://github.com" {
+		return strings.TrimSuffix(serverURLEnv, "/") + "/api/v3"
+	}
+	return ""
+}
+
 // parseRepository extracts owner and repo from GITHUB_REPOSITORY environment variable.
 func parseRepository() (owner, repo string) {
 	repoEnv := os.Getenv("GITHUB_REPOSITORY")
@@ -296,8 +623,8 @@ func validateConfig(cfg Config) {
 		os.Exit(1)
 	}
 
-	if cfg.Token == "" {
-		fmt.Fprintln(os.Stderr, "Error: --token or GITHUB_TOKEN is required")
+	if cfg.Token == "" && cfg.App.AppID == 0 {
+		fmt.Fprintln(os.Stderr, "Error: --token or GITHUB_TOKEN is required (or --app-id for GitHub App authentication)")
 		flag.Usage()
 		os.Exit(1)
 	}
@@ -306,82 +633,130 @@ func validateConfig(cfg Config) {
 		fmt.Fprintln(os.Stderr, "Error: GITHUB_REPOSITORY environment variable is required")
 		os.Exit(1)
 	}
+
+	if err := validateOptions(cfg); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
 }
 
-func generatePRBody(ver, bumpType string, versionFiles []files.VersionFileConfig, ranHelmDocs bool) string {
-	var sb strings.Builder
+// validateOptions checks the optional settings, so that a misconfiguration
+// fails the run before any file is modified.
+func validateOptions(cfg Config) error {
+	if err := cfg.VersionSource.Validate(); err != nil {
+		return fmt.Errorf("invalid --version-source: %w", err)
+	}
+
+	for i, vf := range cfg.VersionFiles {
+		if err := vf.Validate(); err != nil {
+			return fmt.Errorf("invalid --version-files entry %d: %w", i+1, err)
+		}
+	}
+
+	if cfg.VersionSource.IsFile() && cfg.VersionFile == "" {
+		return fmt.Errorf("--version-file is required when reading the version from a file")
+	}
 
-	fmt.Fprintf(&sb, "## Release v%s\n\n", ver)
-	sb.WriteString("### Version Bump\n\n")
-	fmt.Fprintf(&sb, "**%s** release\n\n", bumpType)
-	sb.WriteString("### Files Updated\n\n")
-	sb.WriteString("- `VERSION`\n")
+	if cfg.App.AppID != 0 {
+		if err := cfg.App.Validate(); err != nil {
+			return fmt.Errorf("invalid GitHub App authentication: %w", err)
+		}
+	}
 
-	for _, vf := range versionFiles {
-		fmt.Fprintf(&sb, "- `%s` (path: `%s`)\n", vf.File, vf.Path)
+	for i, hook := range cfg.allHooks() {
+		if err := hook.Validate(); err != nil {
+			return fmt.Errorf("invalid hook %d (%s): %w", i+1, hook.DisplayName(), err)
+		}
 	}
 
-	if ranHelmDocs {
-		sb.WriteString("- Helm chart docs (via helm-docs)\n")
+	if err := cfg.Retry.Validate(); err != nil {
+		return fmt.Errorf("invalid API retry settings: %w", err)
 	}
 
-	sb.WriteString("\n### Next Steps\n\n")
-	sb.WriteString("1. Review this PR\n")
-	sb.WriteString("2. Merge to main\n")
-	sb.WriteString("3. Release automation will handle the rest\n")
-	sb.WriteString("\n### Checklist\n\n")
-	sb.WriteString("- [ ] Version bump is correct\n")
-	sb.WriteString("- [ ] All CI checks pass\n")
+	if err := github.ValidateLabelColor(cfg.LabelColor); err != nil {
+		return fmt.Errorf("invalid --label-color: %w", err)
+	}
 
-	return sb.String()
-}
+	if cfg.AutoMerge != "" {
+		if err := github.ValidateMergeMethod(cfg.AutoMerge); err != nil {
+			return fmt.Errorf("invalid --auto-merge: %w", err)
+		}
+		if cfg.Draft {
+			return fmt.Errorf("--auto-merge cannot be used with --draft")
+		}
+	}
 
-func getModifiedFiles(cfg Config) []string {
-	modifiedFiles := []string{cfg.VersionFile}
-	for _, vf := range cfg.VersionFiles {
-		modifiedFiles = append(modifiedFiles, vf.File)
+	if _, err := templates.New(cfg.Templates); err != nil {
+		return fmt.Errorf("invalid template: %w", err)
 	}
-	return modifiedFiles
+
+	return nil
 }
 
-// runHelmDocs executes helm-docs with the provided arguments and returns the list of modified files.
-func runHelmDocs(argsStr string) ([]string, error) {
-	args := strings.Fields(argsStr)
-	cmd := exec.Command("helm-docs", args...) //nolint:gosec // args are from trusted input
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	if err := cmd.Run(); err != nil {
+// renderTemplates renders the branch name, PR title, PR body and commit message
+// for the release from the configured templates.
+func renderTemplates(cfg Config, currentVersion, newVersion string, allFiles []string) (*templates.Rendered, error) {
+	set, err := templates.New(cfg.Templates)
+	if err != nil {
+		return nil, err
+	}
+
+	changelog, err := readChangelog(cfg.ChangelogFile)
+	if err != nil {
 		return nil, err
 	}
 
-	// Detect files modified by helm-docs using git
-	return getGitModifiedFiles()
+	component := cfg.Component
+	if component == "" {
+		component = cfg.RepoName
+	}
+
+	return set.Render(templates.Data{
+		OldVersion:   currentVersion,
+		NewVersion:   newVersion,
+		BumpType:     cfg.BumpType,
+		Component:    component,
+		Actor:        cfg.TriggeredBy,
+		Changelog:    changelog,
+		VersionFile:  cfg.VersionFile,
+		VersionFiles: cfg.VersionFiles,
+		Files:        allFiles,
+		RanHelmDocs:  cfg.runsHelmDocs(),
+	})
 }
 
-// getGitModifiedFiles returns a list of files that have been modified in the working directory.
-func getGitModifiedFiles() ([]string, error) {
-	cmd := exec.Command("git", "status", "--porcelain")
-	output, err := cmd.Output()
+// readChangelog returns the trimmed contents of the changelog file, or an empty string if none is configured.
+func readChangelog(path string) (string, error) {
+	if path == "" {
+		return "", nil
+	}
+	data, err := os.ReadFile(path)
 	if err != nil {
-		return nil, fmt.Errorf("running git status: %w", err)
+		return "", fmt.Errorf("reading changelog file %s: %w", path, err)
 	}
+	return strings.TrimSpace(string(data)), nil
+}
 
-	var result []string
-	for _, line := range strings.Split(string(output), "\n") {
-		line = strings.TrimSpace(line)
-		if line == "" {
-			continue
-		}
-		// git status --porcelain format: "XY filename" where XY is the status
-		// We want files that are modified (M) or added (A) in the working tree
-		if len(line) >= 3 {
-			file := strings.TrimSpace(line[2:])
-			if file != "" {
-				result = append(result, file)
-			}
-		}
+func getModifiedFiles(cfg Config, currentVersion, newVersion string) []string {
+	var modifiedFiles []string
+	if cfg.VersionFile != "" {
+		modifiedFiles = append(modifiedFiles, cfg.VersionFile)
+	}
+	for _, vf := range cfg.VersionFiles {
+		modifiedFiles = append(modifiedFiles, vf.ModifiedFiles(currentVersion, newVersion)...)
+	}
+	return modifiedFiles
+}
+
+// warning reports a non-fatal problem, as a workflow annotation when running in GitHub Actions.
+func warning(msg string) {
+	if os.Getenv("GITHUB_ACTIONS") == "true" {
+		// Workflow commands are line based, so newlines must be escaped
+		escaped := strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(msg)
+		fmt.Printf("::warning::%s\n", escaped)
+		return
 	}
-	return result, nil
+	fmt.Printf("Warning: %s\n", msg)
 }
 
 func setOutput(name, value string) {
@@ -17,20 +17,28 @@ package main
 import (
 	"context"
 	"errors"
+	"os"
+	"slices"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stacklok/releaseo/internal/files"
+	"github.com/stacklok/releaseo/internal/git"
 	"github.com/stacklok/releaseo/internal/github"
+	"github.com/stacklok/releaseo/internal/hooks"
+	"github.com/stacklok/releaseo/internal/templates"
 )
 
 // mockVersionReader implements files.VersionReader for testing.
 type mockVersionReader struct {
-	version string
-	err     error
+	version  string
+	err      error
+	lastPath string // captures the last path for verification
 }
 
-func (m *mockVersionReader) ReadVersion(_ string) (string, error) {
+func (m *mockVersionReader) ReadVersion(path string) (string, error) {
+	m.lastPath = path
 	return m.version, m.err
 }
 
@@ -45,13 +53,42 @@ func (m *mockVersionWriter) WriteVersion(_, _ string) error {
 
 // mockYAMLUpdater implements files.YAMLUpdater for testing.
 type mockYAMLUpdater struct {
-	err error
+	err     error
+	configs []files.VersionFileConfig // captures the configurations passed, in order
 }
 
-func (m *mockYAMLUpdater) UpdateYAMLFile(_ files.VersionFileConfig, _, _ string) error {
+func (m *mockYAMLUpdater) UpdateYAMLFile(cfg files.VersionFileConfig, _, _ string) error {
+	m.configs = append(m.configs, cfg)
 	return m.err
 }
 
+func (m *mockYAMLUpdater) UpdateYAMLFiles(cfgs []files.VersionFileConfig, currentVersion, newVersion string) []error {
+	errs := make([]error, len(cfgs))
+	for i, cfg := range cfgs {
+		errs[i] = m.UpdateYAMLFile(cfg, currentVersion, newVersion)
+	}
+	return errs
+}
+
+// mockHookRunner implements hooks.Runner for testing.
+type mockHookRunner struct {
+	results map[string]*hooks.Result // keyed by hook display name
+	errs    map[string]error
+	ran     []string // captures the hooks run, in order
+}
+
+func (m *mockHookRunner) Run(_ context.Context, hook hooks.Hook, _ hooks.Vars) (*hooks.Result, error) {
+	name := hook.DisplayName()
+	m.ran = append(m.ran, name)
+	if err := m.errs[name]; err != nil {
+		return nil, err
+	}
+	if result := m.results[name]; result != nil {
+		return result, nil
+	}
+	return &hooks.Result{}, nil
+}
+
 // mockPRCreator implements github.PRCreator for testing.
 type mockPRCreator struct {
 	result      *github.PRResult
@@ -64,6 +101,18 @@ func (m *mockPRCreator) CreateReleasePR(_ context.Context, req github.PRRequest)
 	return m.result, m.err
 }
 
+// mockPRMerger implements github.PRMerger for testing.
+type mockPRMerger struct {
+	result      *github.MergeResult
+	err         error
+	lastRequest github.MergeRequest // captures the last request for verification
+}
+
+func (m *mockPRMerger) MergeReleasePR(_ context.Context, req github.MergeRequest) (*github.MergeResult, error) {
+	m.lastRequest = req
+	return m.result, m.err
+}
+
 // TestUpdateResult_HasErrors tests the HasErrors method of UpdateResult.
 func TestUpdateResult_HasErrors(t *testing.T) {
 	t.Parallel()
@@ -177,6 +226,7 @@ func TestBumpVersion(t *testing.T) {
 		reader         *mockVersionReader
 		wantCurrent    string
 		wantNewVersion string
+		wantReadPath   string
 		wantErr        bool
 		errContains    string
 	}{
@@ -213,6 +263,22 @@ func TestBumpVersion(t *testing.T) {
 			wantNewVersion: "2.0.0",
 			wantErr:        false,
 		},
+		{
+			name: "reads from configured version source file",
+			cfg: Config{
+				BumpType:      "minor",
+				VersionFile:   "VERSION",
+				VersionSource: files.VersionSourceConfig{Type: files.SourceYAML, File: "chart/Chart.yaml", Path: "version"},
+			},
+			reader: &mockVersionReader{
+				version: "0.4.1",
+				err:     nil,
+			},
+			wantCurrent:    "0.4.1",
+			wantNewVersion: "0.5.0",
+			wantReadPath:   "chart/Chart.yaml",
+			wantErr:        false,
+		},
 		{
 			name: "error reading version file",
 			cfg:  Config{BumpType: "patch", VersionFile: "VERSION"},
@@ -272,6 +338,14 @@ func TestBumpVersion(t *testing.T) {
 			if newVersion.String() != tt.wantNewVersion {
 				t.Errorf("bumpVersion() newVersion = %q, want %q", newVersion.String(), tt.wantNewVersion)
 			}
+
+			wantPath := tt.wantReadPath
+			if wantPath == "" {
+				wantPath = tt.cfg.VersionFile
+			}
+			if tt.reader.lastPath != wantPath {
+				t.Errorf("bumpVersion() read from %q, want %q", tt.reader.lastPath, wantPath)
+			}
 		})
 	}
 }
@@ -316,6 +390,21 @@ func TestUpdateAllFiles(t *testing.T) {
 			wantHasErrors:  false,
 			wantErrorCount: 0,
 		},
+		{
+			name: "no version file skips writer",
+			cfg: Config{
+				VersionFile: "",
+				VersionFiles: []files.VersionFileConfig{
+					{File: "package.json", Path: "version"},
+				},
+			},
+			deps: &Dependencies{
+				VersionWriter: &mockVersionWriter{err: errors.New("should not be called")},
+				YAMLUpdater:   &mockYAMLUpdater{err: nil},
+			},
+			wantHasErrors:  false,
+			wantErrorCount: 0,
+		},
 		{
 			name: "version writer error",
 			cfg: Config{
@@ -367,7 +456,7 @@ func TestUpdateAllFiles(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 
-			result := updateAllFiles(tt.cfg, "1.0.0", "1.0.1", tt.deps)
+			result := updateAllFiles(context.Background(), tt.cfg, "1.0.0", "1.0.1", tt.deps)
 
 			if result.HasErrors() != tt.wantHasErrors {
 				t.Errorf("updateAllFiles() HasErrors() = %v, want %v", result.HasErrors(), tt.wantHasErrors)
@@ -380,6 +469,127 @@ func TestUpdateAllFiles(t *testing.T) {
 	}
 }
 
+// TestUpdateAllFiles_OwnVersionStream tests that "same" bumps are resolved to the app's bump type.
+func TestUpdateAllFiles_OwnVersionStream(t *testing.T) {
+	t.Parallel()
+
+	updater := &mockYAMLUpdater{}
+	cfg := Config{
+		BumpType: "minor",
+		VersionFiles: []files.VersionFileConfig{
+			{File: "charts/app", Type: files.TypeHelm, Bump: files.BumpSame},
+			{File: "values.yaml", Path: "chart.version", Bump: "patch"},
+			{File: "values.yaml", Path: "image.tag"},
+		},
+	}
+	result := updateAllFiles(context.Background(), cfg, "1.0.0", "1.1.0", &Dependencies{YAMLUpdater: updater})
+	if result.HasErrors() {
+		t.Fatalf("updateAllFiles() errors: %v", result.CombinedError())
+	}
+
+	var bumps []string
+	for _, vf := range updater.configs {
+		bumps = append(bumps, vf.Bump)
+	}
+	if want := []string{"minor", "patch", ""}; !slices.Equal(bumps, want) {
+		t.Errorf("bumps passed to updater = %q, want %q", bumps, want)
+	}
+}
+
+// TestUpdateAllFiles_Hooks tests that hooks run in phase order and their changes are recorded.
+func TestUpdateAllFiles_Hooks(t *testing.T) {
+	t.Parallel()
+
+	cfg := Config{
+		VersionFile:  "VERSION",
+		HelmDocsArgs: "-c charts",
+		Hooks: []hooks.Hook{
+			{Name: "manifests", Phase: hooks.PhasePost, Command: []string{"make", "manifests"}},
+			{Name: "generate", Phase: hooks.PhasePre, Command: []string{"go", "generate", "./..."}},
+		},
+	}
+
+	tests := []struct {
+		name          string
+		versionErr    error
+		results       map[string]*hooks.Result
+		errs          map[string]error
+		wantRan       []string
+		wantHookFiles []string
+		wantDeleted   []string
+		wantErr       string
+	}{
+		{
+			name: "pre hooks, then helm-docs, then post hooks",
+			results: map[string]*hooks.Result{
+				"generate": {Changes: []git.Change{{Kind: git.Added, Path: "zz_generated.go"}}},
+				"helm-docs": {Changes: []git.Change{
+					{Kind: git.Modified, Path: "charts/app/README.md"},
+					{Kind: git.Deleted, Path: "config/old.yaml"},
+				}},
+				// Recreating a file deleted by an earlier hook commits it again
+				"manifests": {
+					Changes: []git.Change{{Kind: git.Added, Path: "config/old.yaml"}},
+					Ignored: []string{"bin/controller-gen"},
+				},
+			},
+			wantRan:       []string{"generate", "helm-docs", "manifests"},
+			wantHookFiles: []string{"zz_generated.go", "charts/app/README.md", "config/old.yaml"},
+		},
+		{
+			name:    "failing pre hook skips the version bump",
+			errs:    map[string]error{"generate": errors.New("exit status 1")},
+			wantRan: []string{"generate"},
+			wantErr: "running hook generate",
+		},
+		{
+			name:       "failed version update skips post hooks",
+			versionErr: errors.New("write failed"),
+			wantRan:    []string{"generate"},
+			wantErr:    "write failed",
+		},
+		{
+			name: "failing post hook stops later hooks",
+			results: map[string]*hooks.Result{
+				"generate": {Changes: []git.Change{{Kind: git.Renamed, Path: "b.go", OrigPath: "a.go"}}},
+			},
+			errs:          map[string]error{"helm-docs": errors.New("exit status 2")},
+			wantRan:       []string{"generate", "helm-docs"},
+			wantHookFiles: []string{"b.go"},
+			wantDeleted:   []string{"a.go"},
+			wantErr:       "running hook helm-docs",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			runner := &mockHookRunner{results: tt.results, errs: tt.errs}
+			result := updateAllFiles(context.Background(), cfg, "1.0.0", "1.1.0", &Dependencies{
+				VersionWriter: &mockVersionWriter{err: tt.versionErr},
+				YAMLUpdater:   &mockYAMLUpdater{},
+				HookRunner:    runner,
+			})
+
+			if err := result.CombinedError(); tt.wantErr == "" && err != nil {
+				t.Fatalf("updateAllFiles() unexpected error: %v", err)
+			} else if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("updateAllFiles() error = %v, want to contain %q", err, tt.wantErr)
+			}
+			if !slices.Equal(runner.ran, tt.wantRan) {
+				t.Errorf("hooks run = %v, want %v", runner.ran, tt.wantRan)
+			}
+			if !slices.Equal(result.HookFiles, tt.wantHookFiles) {
+				t.Errorf("HookFiles = %v, want %v", result.HookFiles, tt.wantHookFiles)
+			}
+			if !slices.Equal(result.DeletedFiles, tt.wantDeleted) {
+				t.Errorf("DeletedFiles = %v, want %v", result.DeletedFiles, tt.wantDeleted)
+			}
+		})
+	}
+}
+
 // TestCreateReleasePR tests the createReleasePR function.
 func TestCreateReleasePR(t *testing.T) {
 	t.Parallel()
@@ -389,7 +599,7 @@ func TestCreateReleasePR(t *testing.T) {
 		cfg             Config
 		prCreator       *mockPRCreator
 		newVersion      string
-		helmDocsFiles   []string
+		hookFiles       []string
 		wantErr         bool
 		errContains     string
 		wantPRNumber    int
@@ -434,11 +644,11 @@ func TestCreateReleasePR(t *testing.T) {
 				},
 				err: nil,
 			},
-			newVersion:    "2.0.0",
-			helmDocsFiles: []string{"charts/README.md"},
-			wantErr:       false,
-			wantPRNumber:  456,
-			wantPRURL:     "https://github.com/owner/repo/pull/456",
+			newVersion:   "2.0.0",
+			hookFiles:    []string{"charts/README.md"},
+			wantErr:      false,
+			wantPRNumber: 456,
+			wantPRURL:    "https://github.com/owner/repo/pull/456",
 		},
 		{
 			name: "error from pr creator",
@@ -487,7 +697,8 @@ func TestCreateReleasePR(t *testing.T) {
 			t.Parallel()
 
 			ctx := context.Background()
-			result, err := createReleasePR(ctx, tt.cfg, tt.prCreator, tt.newVersion, tt.helmDocsFiles)
+			result, err := createReleasePR(ctx, tt.cfg, tt.prCreator, "1.0.0", tt.newVersion,
+				&UpdateResult{HookFiles: tt.hookFiles})
 
 			if tt.wantErr {
 				if err == nil {
@@ -522,8 +733,107 @@ func TestCreateReleasePR(t *testing.T) {
 	}
 }
 
-// TestGeneratePRBody tests the generatePRBody function.
-func TestGeneratePRBody(t *testing.T) {
+// TestCreateReleasePR_Templates tests that rendered templates are passed through to the PRRequest.
+func TestCreateReleasePR_Templates(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		templates     templates.Config
+		component     string
+		wantBranch    string
+		wantTitle     string
+		wantBody      string
+		wantCommitMsg string
+		wantErr       bool
+	}{
+		{
+			name:          "defaults",
+			wantBranch:    "release/v1.1.0",
+			wantTitle:     "Release v1.1.0",
+			wantBody:      "## Release v1.1.0",
+			wantCommitMsg: "Update release files",
+		},
+		{
+			name: "conventional commits with jira key",
+			templates: templates.Config{
+				Branch:        "release/{{ .Component }}-{{ .NewVersion }}",
+				Title:         "chore(release): {{ .NewVersion }}",
+				Body:          "REL-42: {{ .OldVersion }} -> {{ .NewVersion }} ({{ .BumpType }}) by {{ .Actor }}",
+				CommitMessage: "chore(release): {{ .NewVersion }}\n\nRefs: REL-42",
+			},
+			component:     "operator",
+			wantBranch:    "release/operator-1.1.0",
+			wantTitle:     "chore(release): 1.1.0",
+			wantBody:      "REL-42: 1.0.0 -> 1.1.0 (minor) by testuser",
+			wantCommitMsg: "chore(release): 1.1.0\n\nRefs: REL-42",
+		},
+		{
+			name:       "component defaults to repository name",
+			templates:  templates.Config{Branch: "release/{{ .Component }}/v{{ .NewVersion }}"},
+			wantBranch: "release/repo/v1.1.0",
+		},
+		{
+			name:       "milestone",
+			templates:  templates.Config{Milestone: "v{{ .NewVersion }}"},
+			wantBranch: "release/v1.1.0",
+		},
+		{
+			name:      "invalid template",
+			templates: templates.Config{Title: "{{ .Nope }}"},
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := Config{
+				RepoOwner:   "owner",
+				RepoName:    "repo",
+				BaseBranch:  "main",
+				BumpType:    "minor",
+				VersionFile: "VERSION",
+				TriggeredBy: "testuser",
+				Component:   tt.component,
+				Templates:   tt.templates,
+			}
+			prCreator := &mockPRCreator{result: &github.PRResult{Number: 1}}
+
+			_, err := createReleasePR(context.Background(), cfg, prCreator, "1.0.0", "1.1.0", &UpdateResult{})
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("createReleasePR() error = nil, want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("createReleasePR() unexpected error: %v", err)
+			}
+
+			req := prCreator.lastRequest
+			if req.HeadBranch != tt.wantBranch {
+				t.Errorf("HeadBranch = %q, want %q", req.HeadBranch, tt.wantBranch)
+			}
+			if tt.wantTitle != "" && req.Title != tt.wantTitle {
+				t.Errorf("Title = %q, want %q", req.Title, tt.wantTitle)
+			}
+			if !strings.Contains(req.Body, tt.wantBody) {
+				t.Errorf("Body = %q, want to contain %q", req.Body, tt.wantBody)
+			}
+			if tt.wantCommitMsg != "" && req.CommitMessage != tt.wantCommitMsg {
+				t.Errorf("CommitMessage = %q, want %q", req.CommitMessage, tt.wantCommitMsg)
+			}
+			if tt.templates.Milestone != "" && req.Milestone != "v1.1.0" {
+				t.Errorf("Milestone = %q, want %q", req.Milestone, "v1.1.0")
+			}
+		})
+	}
+}
+
+// TestRenderTemplates_DefaultBody tests the PR body rendered by the default template.
+func TestRenderTemplates_DefaultBody(t *testing.T) {
 	t.Parallel()
 
 	tests := []struct {
@@ -607,17 +917,30 @@ func TestGeneratePRBody(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 
-			body := generatePRBody(tt.version, tt.bumpType, tt.versionFiles, tt.ranHelmDocs)
+			cfg := Config{
+				BumpType:     tt.bumpType,
+				VersionFile:  "VERSION",
+				VersionFiles: tt.versionFiles,
+			}
+			if tt.ranHelmDocs {
+				cfg.HelmDocsArgs = "--chart-search-root=charts"
+			}
+
+			rendered, err := renderTemplates(cfg, "0.9.0", tt.version, getModifiedFiles(cfg, "0.9.0", tt.version))
+			if err != nil {
+				t.Fatalf("renderTemplates() unexpected error: %v", err)
+			}
+			body := rendered.Body
 
 			for _, want := range tt.wantStrings {
 				if !strings.Contains(body, want) {
-					t.Errorf("generatePRBody() = %q, want to contain %q", body, want)
+					t.Errorf("renderTemplates() body = %q, want to contain %q", body, want)
 				}
 			}
 
 			for _, dontWant := range tt.dontWant {
 				if strings.Contains(body, dontWant) {
-					t.Errorf("generatePRBody() = %q, should not contain %q", body, dontWant)
+					t.Errorf("renderTemplates() body = %q, should not contain %q", body, dontWant)
 				}
 			}
 		})
@@ -651,6 +974,15 @@ func TestGetModifiedFiles(t *testing.T) {
 			},
 			wantFiles: []string{"VERSION", "chart/Chart.yaml", "app/values.yaml"},
 		},
+		{
+			name: "no version file",
+			cfg: Config{
+				VersionFiles: []files.VersionFileConfig{
+					{File: "chart/Chart.yaml", Path: "version"},
+				},
+			},
+			wantFiles: []string{"chart/Chart.yaml"},
+		},
 		{
 			name: "custom version file path",
 			cfg: Config{
@@ -664,7 +996,7 @@ func TestGetModifiedFiles(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 
-			got := getModifiedFiles(tt.cfg)
+			got := getModifiedFiles(tt.cfg, "1.0.0", "1.1.0")
 
 			if len(got) != len(tt.wantFiles) {
 				t.Errorf("getModifiedFiles() returned %d files, want %d", len(got), len(tt.wantFiles))
@@ -682,3 +1014,299 @@ func TestGetModifiedFiles(t *testing.T) {
 		})
 	}
 }
+
+// TestResolveVersionFile tests that the VERSION file is optional for non-file version sources.
+func TestResolveVersionFile(t *testing.T) {
+	t.Parallel()
+
+	existing := t.TempDir() + "/VERSION"
+	if err := os.WriteFile(existing, []byte("1.0.0\n"), 0600); err != nil {
+		t.Fatalf("failed to write VERSION file: %v", err)
+	}
+	missing := t.TempDir() + "/VERSION"
+
+	tests := []struct {
+		name   string
+		path   string
+		source files.VersionSourceConfig
+		want   string
+	}{
+		{
+			name:   "file source keeps path even if missing",
+			path:   missing,
+			source: files.VersionSourceConfig{},
+			want:   missing,
+		},
+		{
+			name:   "git tag source keeps existing VERSION file",
+			path:   existing,
+			source: files.VersionSourceConfig{Type: files.SourceGitTag},
+			want:   existing,
+		},
+		{
+			name:   "git tag source skips missing VERSION file",
+			path:   missing,
+			source: files.VersionSourceConfig{Type: files.SourceGitTag},
+			want:   "",
+		},
+		{
+			name:   "yaml source with empty path",
+			path:   "",
+			source: files.VersionSourceConfig{Type: files.SourceYAML, File: "Chart.yaml", Path: "version"},
+			want:   "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := resolveVersionFile(tt.path, tt.source); got != tt.want {
+				t.Errorf("resolveVersionFile() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestCreateReleasePR_PROptions tests that PR metadata options are passed through to the PRRequest.
+func TestCreateReleasePR_PROptions(t *testing.T) {
+	t.Parallel()
+
+	cfg := Config{
+		RepoOwner:     "owner",
+		RepoName:      "repo",
+		BaseBranch:    "main",
+		BumpType:      "patch",
+		VersionFile:   "VERSION",
+		Draft:         true,
+		Labels:        []string{"release", "needs-qa"},
+		LabelColor:    "b60205",
+		Reviewers:     []string{"alice"},
+		TeamReviewers: []string{"platform"},
+		Assignees:     []string{"bob"},
+	}
+	prCreator := &mockPRCreator{result: &github.PRResult{Number: 1, Warnings: []string{"could not assign bob"}}}
+
+	update := &UpdateResult{DeletedFiles: []string{"charts/app/old-README.md"}}
+	if _, err := createReleasePR(context.Background(), cfg, prCreator, "1.0.0", "1.0.1", update); err != nil {
+		t.Fatalf("createReleasePR() unexpected error: %v", err)
+	}
+
+	req := prCreator.lastRequest
+	if strings.Join(req.DeletedFiles, ",") != "charts/app/old-README.md" {
+		t.Errorf("DeletedFiles = %v, want [charts/app/old-README.md]", req.DeletedFiles)
+	}
+	if !req.Draft {
+		t.Error("Draft = false, want true")
+	}
+	if strings.Join(req.Labels, ",") != "release,needs-qa" || req.LabelColor != "b60205" {
+		t.Errorf("Labels = %v (color %q), want [release needs-qa] (color b60205)", req.Labels, req.LabelColor)
+	}
+	if strings.Join(req.Reviewers, ",") != "alice" || strings.Join(req.TeamReviewers, ",") != "platform" {
+		t.Errorf("Reviewers = %v, TeamReviewers = %v", req.Reviewers, req.TeamReviewers)
+	}
+	if strings.Join(req.Assignees, ",") != "bob" {
+		t.Errorf("Assignees = %v, want [bob]", req.Assignees)
+	}
+}
+
+// TestSplitList tests parsing of comma-separated flag values.
+func TestSplitList(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{name: "empty", input: "", want: nil},
+		{name: "single", input: "release", want: []string{"release"}},
+		{name: "whitespace and empty entries", input: " release, ,needs-qa ,", want: []string{"release", "needs-qa"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := splitList(tt.input)
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") || len(got) != len(tt.want) {
+				t.Errorf("splitList(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestMergeReleasePR tests the mergeReleasePR function.
+func TestMergeReleasePR(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		merger  *mockPRMerger
+		wantErr bool
+	}{
+		{
+			name:   "auto-merge enabled",
+			merger: &mockPRMerger{result: &github.MergeResult{Status: github.MergeStatusAutoMergeEnabled}},
+		},
+		{
+			name:   "merged directly",
+			merger: &mockPRMerger{result: &github.MergeResult{Status: github.MergeStatusMerged, SHA: "abc123"}},
+		},
+		{
+			name:   "checks failed is not an error",
+			merger: &mockPRMerger{result: &github.MergeResult{Status: github.MergeStatusChecksFailed, Reason: "failed checks: test"}},
+		},
+		{
+			name:    "api error",
+			merger:  &mockPRMerger{err: errors.New("github api error")},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := Config{RepoOwner: "owner", RepoName: "repo", AutoMerge: "squash", AutoMergeTimeout: time.Minute}
+			err := mergeReleasePR(context.Background(), cfg, tt.merger, &github.PRResult{Number: 42})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("mergeReleasePR() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			req := tt.merger.lastRequest
+			if req.Number != 42 || req.Method != "squash" || req.Timeout != time.Minute {
+				t.Errorf("MergeRequest = %+v, want PR 42, method squash, timeout 1m", req)
+			}
+		})
+	}
+}
+
+// TestValidateOptions tests validation of optional settings.
+func TestValidateOptions(t *testing.T) {
+	t.Parallel()
+
+	valid := Config{VersionFile: "VERSION", LabelColor: "ededed"}
+
+	tests := []struct {
+		name        string
+		modify      func(*Config)
+		errContains string
+	}{
+		{name: "defaults", modify: func(_ *Config) {}},
+		{
+			name:   "auto-merge squash",
+			modify: func(c *Config) { c.AutoMerge = "squash" },
+		},
+		{
+			name:        "invalid auto-merge method",
+			modify:      func(c *Config) { c.AutoMerge = "octopus" },
+			errContains: "--auto-merge",
+		},
+		{
+			name:        "auto-merge with draft",
+			modify:      func(c *Config) { c.AutoMerge = "merge"; c.Draft = true },
+			errContains: "--draft",
+		},
+		{
+			name:        "invalid label color",
+			modify:      func(c *Config) { c.LabelColor = "#fff" },
+			errContains: "--label-color",
+		},
+		{
+			name:        "file source without version file",
+			modify:      func(c *Config) { c.VersionFile = "" },
+			errContains: "--version-file",
+		},
+		{
+			name: "hooks",
+			modify: func(c *Config) {
+				c.Hooks = []hooks.Hook{{Command: []string{"make", "manifests"}, Phase: hooks.PhasePre}}
+			},
+		},
+		{
+			name:        "invalid hook",
+			modify:      func(c *Config) { c.Hooks = []hooks.Hook{{Name: "gen", Timeout: "soon"}} },
+			errContains: "invalid hook 1 (gen)",
+		},
+		{
+			name:        "invalid version file",
+			modify:      func(c *Config) { c.VersionFiles = []files.VersionFileConfig{{File: "charts/app", Type: "chart"}} },
+			errContains: "--version-files entry 1",
+		},
+		{
+			name:        "invalid version source",
+			modify:      func(c *Config) { c.VersionSource = files.VersionSourceConfig{Type: "toml"} },
+			errContains: "--version-source",
+		},
+		{
+			name: "github app",
+			modify: func(c *Config) {
+				c.App = github.AppConfig{AppID: 1, PrivateKey: []byte("key"), Owner: "o", Repo: "r"}
+			},
+		},
+		{
+			name:        "negative retry attempts",
+			modify:      func(c *Config) { c.Retry.MaxAttempts = -1 },
+			errContains: "retry",
+		},
+		{
+			name:        "github app without private key",
+			modify:      func(c *Config) { c.App = github.AppConfig{AppID: 1, InstallationID: 2} },
+			errContains: "GitHub App",
+		},
+		{
+			name:        "invalid template",
+			modify:      func(c *Config) { c.Templates.Branch = "release {{ .NewVersion }}" },
+			errContains: "invalid template",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			cfg := valid
+			tt.modify(&cfg)
+
+			err := validateOptions(cfg)
+			if tt.errContains == "" {
+				if err != nil {
+					t.Errorf("validateOptions() unexpected error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+				t.Errorf("validateOptions() error = %v, want to contain %q", err, tt.errContains)
+			}
+		})
+	}
+}
+
+// TestResolveAPIURL tests GitHub API URL resolution from flags and the Actions environment.
+func TestResolveAPIURL(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		flagURL   string
+		apiURL    string
+		serverURL string
+		want      string
+	}{
+		{name: "nothing set", want: ""},
+		{name: "flag wins", flagURL: "https://flag.example.com/api/v3", apiURL: "https://env.example.com/api/v3",
+			want: "https://flag.example.com/api/v3"},
+		{name: "GITHUB_API_URL", apiURL: "https://ghes.example.com/api/v3", serverURL: "https://ghes.example.com",
+			want: "https://ghes.example.com/api/v3"},
+		{name: "derived from GITHUB_SERVER_URL", serverURL: "https://ghes.example.com/", want: "https://ghes.example.com/api/v3"},
+		{name: "github.com server URL", serverURL: "https://github.com", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := resolveAPIURL(tt.flagURL, tt.apiURL, tt.serverURL); got != tt.want {
+				t.Errorf("resolveAPIURL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}